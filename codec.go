@@ -0,0 +1,143 @@
+package ddbstruct
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// EncoderFunc encodes v as a DynamoDB attribute.
+type EncoderFunc func(v interface{}) (types.AttributeValue, error)
+
+// DecoderFunc decodes a DynamoDB attribute. The returned value must be
+// assignable (or convertible) to the type the decoder was registered for.
+type DecoderFunc func(av types.AttributeValue) (interface{}, error)
+
+type codec struct {
+	enc EncoderFunc
+	dec DecoderFunc
+}
+
+type codecRegistry struct {
+	sync.RWMutex
+	types map[reflect.Type]*codec
+	names map[string]*codec
+}
+
+var codecs = codecRegistry{types: map[reflect.Type]*codec{}, names: map[string]*codec{}}
+
+// RegisterCodec makes enc and dec the encoding for every field of type t (or
+// a pointer to t), ahead of any encoding ddbstruct would otherwise guess. It
+// is intended for types that can't be given marshaling methods, such as types
+// from other packages. Registering t again replaces the earlier codec.
+//
+// Struct metadata that was computed before the call may have picked a
+// different encoding, so it is discarded and rebuilt as structs are next used.
+func RegisterCodec(t reflect.Type, enc EncoderFunc, dec DecoderFunc) {
+	if t == nil || enc == nil || dec == nil {
+		panic("RegisterCodec requires a type, an encoder and a decoder")
+	}
+
+	// lock in the same order as structMetadataCache.get, which consults the
+	// registry while holding the cache lock
+	cache.Lock()
+	defer cache.Unlock()
+	codecs.Lock()
+	defer codecs.Unlock()
+
+	codecs.types[t] = &codec{enc: enc, dec: dec}
+	cache.types = map[reflect.Type]structMetadata{}
+}
+
+// RegisterNamedCodec makes enc and dec available to any field tagged with
+// t=name, whatever the field's type. The encoder is passed the field's value
+// (with any pointer followed), and the decoder's result is converted to the
+// field's type. This lets one type be stored differently in different fields.
+// Registering a name again replaces the earlier codec, and, as with
+// RegisterCodec, cached struct metadata is discarded.
+//
+// Names that typecalc already understands, such as "json" or "epoch", and
+// names containing a colon are reserved and cause a panic, as do names that
+// can't appear in a tag.
+func RegisterNamedCodec(name string, enc EncoderFunc, dec DecoderFunc) {
+	if name == "" || enc == nil || dec == nil {
+		panic("RegisterNamedCodec requires a name, an encoder and a decoder")
+	}
+	if strings.ContainsAny(name, ",\"") {
+		panic(fmt.Errorf("codec name %q cannot be used in a struct tag", name))
+	}
+	if reservedEncTypes[name] || strings.ContainsRune(name, ':') {
+		panic(fmt.Errorf("codec name %q is reserved", name))
+	}
+
+	cache.Lock()
+	defer cache.Unlock()
+	codecs.Lock()
+	defer codecs.Unlock()
+
+	codecs.names[name] = &codec{enc: enc, dec: dec}
+	cache.types = map[reflect.Type]structMetadata{}
+}
+
+// lookupName finds the codec registered as name.
+func (r *codecRegistry) lookupName(name string) *codec {
+	r.RLock()
+	defer r.RUnlock()
+	return r.names[name]
+}
+
+// lookup finds the codec registered for t. If t is a pointer and only its
+// element type is registered, deref is set.
+func (r *codecRegistry) lookup(t reflect.Type) (c *codec, deref bool) {
+	r.RLock()
+	defer r.RUnlock()
+	if c, ok := r.types[t]; ok {
+		return c, false
+	}
+	if t.Kind() == reflect.Pointer {
+		if c, ok := r.types[t.Elem()]; ok {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+func encCodec(c *codec, deref bool) encodeFunc {
+	return func(s interface{}, f int) types.AttributeValue {
+		d := reflect.ValueOf(s).Elem().Field(f)
+		if deref {
+			d = readF(s, f)
+		}
+		av, err := c.enc(d.Interface())
+		if err != nil {
+			panic(err)
+		}
+		return av
+	}
+}
+func decCodec(c *codec, deref bool) decodeFunc {
+	return func(s interface{}, f int, av types.AttributeValue) {
+		d := reflect.ValueOf(s).Elem().Field(f)
+		if deref {
+			d = getF(s, f)
+		}
+		v, err := c.dec(av)
+		if err != nil {
+			panic(err)
+		}
+		rv := reflect.ValueOf(v)
+		switch {
+		case !rv.IsValid():
+			d.Set(reflect.Zero(d.Type()))
+		case rv.Type().AssignableTo(d.Type()):
+			d.Set(rv)
+		case rv.Type().ConvertibleTo(d.Type()):
+			d.Set(rv.Convert(d.Type()))
+		default:
+			panic(fmt.Errorf("decoder returned %s, which cannot be stored in %s", rv.Type(), d.Type()))
+		}
+	}
+}
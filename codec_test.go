@@ -0,0 +1,192 @@
+package ddbstruct
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// codecUpper stands in for a type from another package that has no
+// marshaling methods of its own.
+type codecUpper string
+
+func registerCodecUpper() {
+	RegisterCodec(reflect.TypeOf(codecUpper("")),
+		func(v interface{}) (types.AttributeValue, error) {
+			return &types.AttributeValueMemberS{Value: strings.ToUpper(string(v.(codecUpper)))}, nil
+		},
+		func(av types.AttributeValue) (interface{}, error) {
+			s, ok := av.(*types.AttributeValueMemberS)
+			if !ok {
+				return nil, errors.New("expected a string")
+			}
+			return codecUpper(strings.ToLower(s.Value)), nil
+		})
+}
+
+func TestCodecRoundTrip(t *testing.T) {
+	type z struct {
+		X codecUpper
+		Y *codecUpper
+	}
+	registerCodecUpper()
+	y := codecUpper("ptr")
+	in := &z{X: "abc", Y: &y}
+	m, err := cache.get(in).encode(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := m["X"].(*types.AttributeValueMemberS).Value; s != "ABC" {
+		t.Fatalf("expected \"ABC\", got %q", s)
+	}
+	if s := m["Y"].(*types.AttributeValueMemberS).Value; s != "PTR" {
+		t.Fatalf("expected \"PTR\", got %q", s)
+	}
+	out := &z{}
+	if err = cache.get(out).decode(out, m, false); err != nil {
+		t.Fatal(err)
+	}
+	if out.X != in.X || out.Y == nil || *out.Y != *in.Y {
+		t.Fatalf("expected %+v, got %+v", in, out)
+	}
+}
+
+func TestCodecInvalidatesCache(t *testing.T) {
+	type late string
+	type z struct{ X late }
+	in := &z{X: "abc"}
+	m, err := cache.get(in).encode(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectT(t, new(types.AttributeValueMemberS), m["X"])
+
+	RegisterCodec(reflect.TypeOf(late("")),
+		func(v interface{}) (types.AttributeValue, error) {
+			return &types.AttributeValueMemberN{Value: "1"}, nil
+		},
+		func(av types.AttributeValue) (interface{}, error) {
+			return "x", nil // a string, converted to late
+		})
+	m, err = cache.get(in).encode(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectT(t, new(types.AttributeValueMemberN), m["X"])
+	out := &z{}
+	if err = cache.get(out).decode(out, m, false); err != nil {
+		t.Fatal(err)
+	}
+	if out.X != "x" {
+		t.Fatalf("expected \"x\", got %q", out.X)
+	}
+}
+
+func TestCodecDecodeWrongType(t *testing.T) {
+	type wrong struct{}
+	type z struct{ X wrong }
+	RegisterCodec(reflect.TypeOf(wrong{}),
+		func(v interface{}) (types.AttributeValue, error) {
+			return &types.AttributeValueMemberNULL{Value: true}, nil
+		},
+		func(av types.AttributeValue) (interface{}, error) {
+			return 12, nil
+		})
+	out := &z{}
+	f := typecalcField(t, out, 0)
+	expectPanic(t, func() { f.dec(out, 0, &types.AttributeValueMemberNULL{Value: true}) })
+}
+
+func TestCodecConcurrentRegistration(t *testing.T) {
+	type z struct {
+		X codecUpper
+		Y []codecUpper
+	}
+	var wg sync.WaitGroup
+	for n := 0; n < 8; n++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			registerCodecUpper()
+		}()
+		go func() {
+			defer wg.Done()
+			in := &z{X: "a", Y: []codecUpper{"b"}}
+			if _, err := cache.get(in).encode(in); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func registerNamedCents() {
+	RegisterNamedCodec("testcents",
+		func(v interface{}) (types.AttributeValue, error) {
+			return &types.AttributeValueMemberS{Value: fmt.Sprintf("$%d.%02d", v.(int64)/100, v.(int64)%100)}, nil
+		},
+		func(av types.AttributeValue) (interface{}, error) {
+			var d, c int64
+			_, err := fmt.Sscanf(av.(*types.AttributeValueMemberS).Value, "$%d.%02d", &d, &c)
+			return d*100 + c, err
+		})
+}
+
+func TestNamedCodecRoundTrip(t *testing.T) {
+	type price int64
+	type z struct {
+		X int64 `ddb:"t=testcents"`
+		Y int64
+		Z *price `ddb:"t=testcents"`
+	}
+	registerNamedCents()
+	in := &z{X: 1234, Y: 1234}
+	f := typecalcField(t, in, 0)
+	av := f.enc(in, 0)
+	if s := av.(*types.AttributeValueMemberS).Value; s != "$12.34" {
+		t.Fatalf("expected \"$12.34\", got %q", s)
+	}
+	expectT(t, new(types.AttributeValueMemberN), typecalcField(t, in, 1).enc(in, 1))
+	out := &z{}
+	f.dec(out, 0, av)
+	if in.X != out.X {
+		t.Fatalf("expected %d, got %d", in.X, out.X)
+	}
+	// the decoder returns an int64, which is converted to price
+	typecalcField(t, in, 2).dec(out, 2, av)
+	if out.Z == nil || *out.Z != 1234 {
+		t.Fatalf("expected 1234, got %v", out.Z)
+	}
+}
+
+func TestNamedCodecReserved(t *testing.T) {
+	expectPanic(t, func() { RegisterNamedCodec("json", nil, nil) })
+	expectPanic(t, func() {
+		RegisterNamedCodec("json",
+			func(v interface{}) (types.AttributeValue, error) { return nil, nil },
+			func(av types.AttributeValue) (interface{}, error) { return nil, nil })
+	})
+	expectPanic(t, func() {
+		RegisterNamedCodec("a,b",
+			func(v interface{}) (types.AttributeValue, error) { return nil, nil },
+			func(av types.AttributeValue) (interface{}, error) { return nil, nil })
+	})
+}
+
+func TestNamedCodecUnknown(t *testing.T) {
+	type z struct {
+		X int64 `ddb:"t=nosuchcodec"`
+	}
+	f, err := parseFieldTag(reflect.TypeOf(z{}), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = f.typecalc(); err == nil {
+		t.Fatal("expected typecalc to fail")
+	}
+}
@@ -0,0 +1,76 @@
+package ddbstruct
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DynamoDB numbers carry up to 38 significant digits, and their magnitude
+// must be between 1E-130 and 9.99...E+125 (or be zero).
+const (
+	maxNumberDigits   = 38
+	minNumberExponent = -130
+	maxNumberExponent = 125
+)
+
+// Decimal is a decimal number held as a string, so it can carry all of the
+// precision a DynamoDB number allows without passing through a float. It is
+// stored as an N attribute. The zero value (an empty string) is stored as 0.
+type Decimal string
+
+// Validate reports whether d is a number that DynamoDB can store.
+func (d Decimal) Validate() error {
+	if d == "" {
+		return nil
+	}
+	return checkNumber(string(d))
+}
+
+// splitNumber breaks a decimal number, such as "-12.50" or "1.5E+7", into its
+// sign, its significant digits with leading and trailing zeros removed, and
+// the power of ten those digits are multiplied by. For zero, digits is empty.
+func splitNumber(s string) (neg bool, digits string, exp int, err error) {
+	n := s
+	if len(n) > 0 && (n[0] == '-' || n[0] == '+') {
+		neg = n[0] == '-'
+		n = n[1:]
+	}
+	if e := strings.IndexAny(n, "eE"); e >= 0 {
+		exp, err = strconv.Atoi(n[e+1:])
+		if err != nil {
+			return false, "", 0, fmt.Errorf("number %q has an invalid exponent", s)
+		}
+		n = n[:e]
+	}
+	if p := strings.IndexByte(n, '.'); p >= 0 {
+		exp -= len(n) - p - 1
+		n = n[:p] + n[p+1:]
+	}
+	if n == "" || strings.Trim(n, "0123456789") != "" {
+		return false, "", 0, fmt.Errorf("%q is not a decimal number", s)
+	}
+	n = strings.TrimLeft(n, "0")
+	trimmed := strings.TrimRight(n, "0")
+	exp += len(n) - len(trimmed)
+	return neg, trimmed, exp, nil
+}
+
+// checkNumber reports whether s is a number DynamoDB will accept.
+func checkNumber(s string) error {
+	_, digits, exp, err := splitNumber(s)
+	if err != nil {
+		return err
+	}
+	if digits == "" { // zero
+		return nil
+	}
+	if len(digits) > maxNumberDigits {
+		return fmt.Errorf("number %s has %d significant digits, more than the %d DynamoDB allows", s, len(digits), maxNumberDigits)
+	}
+	// the exponent of the leading digit, as if written d.ddd * 10^e
+	if e := exp + len(digits) - 1; e < minNumberExponent || e > maxNumberExponent {
+		return fmt.Errorf("number %s is outside the range DynamoDB allows", s)
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package ddbstruct
+
+import "testing"
+
+func TestCheckNumber(t *testing.T) {
+	for _, s := range []string{
+		"0", "-0", "0.000", "12.34", "-12.34", "1E+125", "9.9999999999999999999999999999999999999E+125",
+		"1E-130", "12345678901234567890123456789012345678", "1234567890123456789012345678901234567800000",
+		"0.00012345678901234567890123456789012345678", "+5", "5.", ".5", "1e3",
+	} {
+		if err := checkNumber(s); err != nil {
+			t.Errorf("expected %q to be valid: %v", s, err)
+		}
+	}
+	for _, s := range []string{
+		"", "-", "abc", "1.2.3", "1E", "1E+x", "NaN", "Inf", "1E+126", "1E-131", "10E+125",
+		"123456789012345678901234567890123456789", "1.23456789012345678901234567890123456789",
+	} {
+		if err := checkNumber(s); err == nil {
+			t.Errorf("expected %q to be invalid", s)
+		}
+	}
+}
+
+func TestDecimalValidate(t *testing.T) {
+	if err := Decimal("").Validate(); err != nil {
+		t.Errorf("expected zero value to be valid: %v", err)
+	}
+	if err := Decimal("1.5").Validate(); err != nil {
+		t.Errorf("expected 1.5 to be valid: %v", err)
+	}
+	if err := Decimal("1,5").Validate(); err == nil {
+		t.Error("expected 1,5 to be invalid")
+	}
+}
@@ -3,6 +3,8 @@ package ddbstruct
 import (
 	"fmt"
 	"reflect"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
@@ -15,23 +17,46 @@ var typeTime = reflect.TypeOf(time.Time{})
 var typeDuration = reflect.TypeOf(time.Duration(0))
 var typeBytes = reflect.TypeOf([]byte{})
 
+const dateLayout = "2006-01-02"
+
 func (f *field) tryBasicMarshaling() bool {
-	switch f.gotype.Kind() {
+	t := indirectType(f.gotype) // pointers are followed by getF and readF
+	switch t {
+	case typeBigInt:
+		f.enc, f.dec = encBigInt, decBigInt
+		return true
+	case typeBigFloat:
+		f.enc, f.dec = encBigFloat, decBigFloat
+		return true
+	case typeBigRat:
+		f.enc, f.dec = encBigRat, decBigRat
+		return true
+	case typeDecimal:
+		f.enc, f.dec = encDecimal, decDecimal
+		return true
+	}
+	if t != f.gotype && isTextEncoder(f.gotype) {
+		// a pointer to a type that marshals itself as text is left to
+		// tryInterfaceMarshaling, which is how such pointers were always stored
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		if isTextEncoder(f.gotype) {
+			// these kinds were once left to tryInterfaceMarshaling, so types
+			// that marshal themselves as text keep being stored that way
+			return false
+		}
+	}
+	switch t.Kind() {
 	case reflect.String:
 		f.enc, f.dec = encString, decString
 		return true
-	case reflect.Int:
-	case reflect.Int8:
-	case reflect.Int16:
-	case reflect.Int32:
-	case reflect.Int64:
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		f.enc, f.dec = encInt, decInt
 		return true
-	case reflect.Uint:
-	case reflect.Uint8:
-	case reflect.Uint16:
-	case reflect.Uint32:
-	case reflect.Uint64:
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
 		f.enc, f.dec = encUint, decUint
 		return true
 	case reflect.Float32:
@@ -44,7 +69,7 @@ func (f *field) tryBasicMarshaling() bool {
 		f.enc, f.dec = encBool, decBool
 		return true
 	}
-	switch f.gotype {
+	switch t {
 	case typeBytes:
 		f.enc, f.dec = encBytes, decBytes
 		return true
@@ -52,6 +77,10 @@ func (f *field) tryBasicMarshaling() bool {
 		f.enc, f.dec = encDurationString, decDurationString
 		return true
 	}
+	if isAnyType(f.gotype) {
+		f.enc, f.dec = encAny, decAny
+		return true
+	}
 	return false
 }
 
@@ -69,14 +98,132 @@ func (f *field) tryInterfaceMarshaling() bool {
 	return true
 }
 
+// tryCompositeMarshaling handles types that are built out of other types. If
+// the type matches but its contents can't be encoded, the error explains why.
+func (f *field) tryCompositeMarshaling() (bool, error) {
+	if t := indirectType(f.gotype); t.Kind() == reflect.Array && isByteSeq(t) {
+		f.enc, f.dec = encByteArray, decByteArray // like []byte, rather than a list of numbers
+		return true, nil
+	}
+	switch f.gotype.Kind() {
+	case reflect.Slice, reflect.Array:
+		ec, err := newValueCodec(f.name+"[]", f.gotype.Elem())
+		if err != nil {
+			return true, err
+		}
+		f.enc, f.dec = encList(ec), decList(ec)
+		return true, nil
+	case reflect.Map:
+		if isMapSet(f.gotype) {
+			return true, f.setcalc()
+		}
+		kc, err := newMapKeyCodec(f.gotype.Key())
+		if err != nil {
+			return true, fmt.Errorf("field %q: %w", f.name, err)
+		}
+		ec, err := newValueCodec(f.name+"{}", f.gotype.Elem())
+		if err != nil {
+			return true, err
+		}
+		f.enc, f.dec = encMap(kc, ec), decMap(kc, ec)
+		return true, nil
+	case reflect.Struct:
+		f.enc, f.dec = encStruct, decStruct
+		return true, nil
+	case reflect.Pointer:
+		if f.gotype.Elem().Kind() == reflect.Struct {
+			f.enc, f.dec = encStruct, decStruct
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *field) setcalc() error {
+	sc, err := newSetCodec(f.name, f.gotype)
+	if err != nil {
+		return err
+	}
+	f.enc, f.dec = encSet(sc), decSet(sc)
+	f.set = true
+	return nil
+}
+
+// reservedEncTypes are the t= options handled by typecalc itself, which can't
+// be used as the names of named codecs. Names containing a colon are also
+// reserved, for options that take a parameter (such as fixed:2 or layout:15h).
+var reservedEncTypes = map[string]bool{
+	"string": true, "binary": true, "bytes": true, "json": true, "doc": true, "gob": true, "set": true, "lex": true,
+	"hex": true, "base64": true,
+	"nano": true, "nanoseconds": true, "epoch": true, "seconds": true,
+	"millis": true, "milliseconds": true, "micros": true, "microseconds": true,
+	"date": true, "rfc3339": true, "iso8601": true,
+}
+
+// encTypeParam returns the parameter of a t= option written as name:param.
+func (f *field) encTypeParam(name string) (string, bool) {
+	if !strings.HasPrefix(f.enctype, name+":") {
+		return "", false
+	}
+	return f.enctype[len(name)+1:], true
+}
+
+func (f *field) fixedcalc(p string) error {
+	scale, err := strconv.Atoi(p)
+	if err != nil || scale < 0 || scale > maxNumberDigits {
+		return fmt.Errorf("field %q has invalid fixed point scale %q", f.name, p)
+	}
+	switch indirectType(f.gotype).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		f.enc, f.dec = encFixed(scale), decFixed(scale)
+		return nil
+	}
+	return fmt.Errorf("field %q (a %s) must be an integer to be encoded as fixed point", f.name, f.gotype)
+}
+
+// ttlcalc picks the encoding for a TTL field, which is always epoch seconds.
+func (f *field) ttlcalc() error {
+	if f.enctype != "" && f.enctype != "epoch" && f.enctype != "seconds" {
+		return fmt.Errorf("field %q tagged as ttl is always encoded in epoch seconds, not as %q", f.name, f.enctype)
+	}
+	switch indirectType(f.gotype) {
+	case typeTime:
+		f.enc, f.dec = encTimeEpoch, decTimeEpoch
+		return nil
+	case typeDuration:
+		f.enc, f.dec = encTTLDuration, decTTLDuration
+		return nil
+	}
+	return fmt.Errorf("field %q tagged as ttl, but type %s is not a time or duration", f.name, f.gotype)
+}
+
 func (f *field) typecalc() error {
+	if f.ttl {
+		return f.ttlcalc()
+	}
 	if f.enctype == "" { // with no explicit type, let's start by guessing
+		if c, deref := codecs.lookup(f.gotype); c != nil { // registered with RegisterCodec
+			f.enc, f.dec = encCodec(c, deref), decCodec(c, deref)
+			return nil
+		}
+		if isAttributeEncoder(f.gotype) { // knows how to encode itself, so don't guess
+			f.enc, f.dec = encAttribute, decAttribute
+			return nil
+		}
+		if f.utc && indirectType(f.gotype) == typeTime { // RFC3339, as MarshalText would write, but in UTC
+			f.enc, f.dec = encTimeLayout(time.RFC3339Nano, true), decTimeLayout(time.RFC3339Nano)
+			return nil
+		}
 		if f.tryBasicMarshaling() { // matches basic types
 			return nil
 		}
 		if f.tryInterfaceMarshaling() { // uses standard interfaces
 			return nil
 		}
+		if ok, err := f.tryCompositeMarshaling(); ok { // lists, maps and nested structs
+			return err
+		}
 		return fmt.Errorf("unable to guess encoding for %q field of type %s", f.name, f.gotype)
 	}
 	switch f.enctype {
@@ -95,11 +242,25 @@ func (f *field) typecalc() error {
 			f.enc, f.dec = encBytes, decBytes
 			return nil
 		}
+		if t := indirectType(f.gotype); t.Kind() == reflect.Array && isByteSeq(t) {
+			f.enc, f.dec = encByteArray, decByteArray
+			return nil
+		}
 		if isBinEncoder(f.gotype) {
 			f.enc, f.dec = encBinary, decBinary
 			return nil
 		}
 		return fmt.Errorf("field %q cannot be typed as binary automatically", f.name)
+	case "hex":
+		if isByteSeq(indirectType(f.gotype)) {
+			f.enc, f.dec = encHex, decHex
+			return nil
+		}
+	case "base64":
+		if isByteSeq(indirectType(f.gotype)) {
+			f.enc, f.dec = encBase64, decBase64
+			return nil
+		}
 	case "json":
 		if isJSONEncoder(f.gotype) {
 			f.enc, f.dec = encJSON, decJSON
@@ -107,8 +268,32 @@ func (f *field) typecalc() error {
 		}
 		f.enc, f.dec = encJSONRaw, decJSONRaw
 		return nil
+	case "gob":
+		f.enc, f.dec = encGob, decGob
+		return nil
+	case "doc":
+		f.enc, f.dec = encJSONDoc, decJSONDoc
+		return nil
+	case "set":
+		return f.setcalc()
+	case "lex":
+		if indirectType(f.gotype) == typeTime {
+			f.enc, f.dec = encLexTime, decLexTime
+			return nil
+		}
+		switch indirectType(f.gotype).Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			f.enc, f.dec = encLexInt, decLexInt
+			return nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			f.enc, f.dec = encLexUint, decLexUint
+			return nil
+		case reflect.Float32, reflect.Float64:
+			f.enc, f.dec = encLexFloat, decLexFloat
+			return nil
+		}
 	case "nano", "nanoseconds":
-		switch f.gotype {
+		switch indirectType(f.gotype) {
 		case typeDuration:
 			f.enc, f.dec = encDurationNano, decDurationNano
 			return nil
@@ -117,7 +302,7 @@ func (f *field) typecalc() error {
 			return nil
 		}
 	case "epoch", "seconds":
-		switch f.gotype {
+		switch indirectType(f.gotype) {
 		case typeDuration:
 			f.enc, f.dec = encDurationSec, decDurationSec
 			return nil
@@ -125,6 +310,82 @@ func (f *field) typecalc() error {
 			f.enc, f.dec = encTimeEpoch, decTimeEpoch
 			return nil
 		}
+	case "millis", "milliseconds":
+		switch indirectType(f.gotype) {
+		case typeDuration:
+			f.enc, f.dec = encDurationMilli, decDurationMilli
+			return nil
+		case typeTime:
+			f.enc, f.dec = encTimeMilli, decTimeMilli
+			return nil
+		}
+	case "micros", "microseconds":
+		switch indirectType(f.gotype) {
+		case typeDuration:
+			f.enc, f.dec = encDurationMicro, decDurationMicro
+			return nil
+		case typeTime:
+			f.enc, f.dec = encTimeMicro, decTimeMicro
+			return nil
+		}
+	case "date":
+		if indirectType(f.gotype) == typeTime {
+			f.enc, f.dec = encTimeLayout(dateLayout, f.utc), decTimeLayout(dateLayout)
+			return nil
+		}
+	case "rfc3339":
+		if indirectType(f.gotype) == typeTime {
+			f.enc, f.dec = encTimeLayout(time.RFC3339, f.utc), decTimeLayout(time.RFC3339)
+			return nil
+		}
+	case "iso8601":
+		if indirectType(f.gotype) == typeDuration {
+			f.enc, f.dec = encDurationISO, decDurationISO
+			return nil
+		}
+	default:
+		if p, ok := f.encTypeParam("fixed"); ok {
+			return f.fixedcalc(p)
+		}
+		if p, ok := f.encTypeParam("layout"); ok && indirectType(f.gotype) == typeTime {
+			f.enc, f.dec = encTimeLayout(p, f.utc), decTimeLayout(p)
+			return nil
+		}
+		if c := codecs.lookupName(f.enctype); c != nil { // registered with RegisterNamedCodec
+			f.enc, f.dec = encCodec(c, true), decCodec(c, true)
+			return nil
+		}
 	}
 	return fmt.Errorf("cannot encode field %q (a %s) as %q", f.name, f.gotype, f.enctype)
 }
+
+// valueCodec encodes and decodes values that aren't struct fields, such as
+// the elements of a slice, using the same encoding that typecalc would pick
+// for a field of the same type.
+type valueCodec struct {
+	box reflect.Type
+	f   field
+}
+
+func newValueCodec(name string, t reflect.Type) (*valueCodec, error) {
+	ec := &valueCodec{box: boxType(t), f: field{name: name, gotype: t}}
+	if err := ec.f.typecalc(); err != nil {
+		return nil, err
+	}
+	return ec, nil
+}
+
+func (ec *valueCodec) encode(v reflect.Value) types.AttributeValue {
+	b := reflect.New(ec.box)
+	b.Elem().Field(0).Set(v)
+	return ec.f.enc(b.Interface(), 0)
+}
+
+func (ec *valueCodec) decode(v reflect.Value, av types.AttributeValue) {
+	if decodeNull(v, av) {
+		return
+	}
+	b := reflect.New(ec.box)
+	ec.f.dec(b.Interface(), 0, av)
+	v.Set(b.Elem().Field(0))
+}
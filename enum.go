@@ -0,0 +1,154 @@
+package ddbstruct
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// RegisterEnum stores an integer enum type as a string attribute, using the
+// names in table, which must be a map from the enum type to its names, such
+// as map[Status]string{StatusActive: "ACTIVE", StatusClosed: "CLOSED"}.
+// Encoding a value that isn't in the table, or decoding a name that isn't,
+// is an error. RegisterEnum panics if table isn't such a map, or if two values
+// share a name.
+//
+// RegisterEnum is built on RegisterCodec, and has the same effect on cached
+// struct metadata.
+func RegisterEnum(table interface{}) {
+	tv := reflect.ValueOf(table)
+	if tv.Kind() != reflect.Map || tv.Type().Elem().Kind() != reflect.String || !isIntegerKind(tv.Type().Key().Kind()) {
+		panic(fmt.Errorf("enum table must map an integer type to strings, not %T", table))
+	}
+	et := tv.Type().Key()
+	names := make(map[interface{}]string, tv.Len())
+	values := make(map[string]interface{}, tv.Len())
+	iter := tv.MapRange()
+	for iter.Next() {
+		n := iter.Value().String()
+		if _, dup := values[n]; dup {
+			panic(fmt.Errorf("enum %s uses the name %q more than once", et, n))
+		}
+		names[iter.Key().Interface()] = n
+		values[n] = iter.Key().Interface()
+	}
+
+	RegisterCodec(et,
+		func(v interface{}) (types.AttributeValue, error) {
+			n, ok := names[v]
+			if !ok {
+				return nil, fmt.Errorf("%v is not a known value of enum %s", v, et)
+			}
+			return &types.AttributeValueMemberS{Value: n}, nil
+		},
+		func(av types.AttributeValue) (interface{}, error) {
+			s, ok := av.(*types.AttributeValueMemberS)
+			if !ok {
+				return nil, fmt.Errorf("enum %s must be decoded from a string, not %s", et, attrValString(av))
+			}
+			v, ok := values[s.Value]
+			if !ok {
+				return nil, fmt.Errorf("%q is not a known name of enum %s", s.Value, et)
+			}
+			return v, nil
+		})
+}
+
+func isIntegerKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// RegisterFlags stores an integer bitmask type as a string set of flag names,
+// using the names in table, which must be a map from the bitmask type to the
+// name of each flag, such as map[Perms]string{PermRead: "READ", PermWrite:
+// "WRITE"}. Decoding ORs the named flags back together. Encoding a value with
+// bits that aren't covered by the table, or decoding a name that isn't in it,
+// is an error. As with any set, a value with no flags set can't be stored, so
+// such fields usually want the opt tag.
+//
+// RegisterFlags panics if table isn't such a map, or if a flag is zero,
+// overlaps another flag, or shares a name with one.
+func RegisterFlags(table interface{}) {
+	tv := reflect.ValueOf(table)
+	if tv.Kind() != reflect.Map || tv.Type().Elem().Kind() != reflect.String || !isIntegerKind(tv.Type().Key().Kind()) {
+		panic(fmt.Errorf("flag table must map an integer type to strings, not %T", table))
+	}
+	ft := tv.Type().Key()
+	flags := make([]flagName, 0, tv.Len())
+	values := make(map[string]uint64, tv.Len())
+	var all uint64
+	iter := tv.MapRange()
+	for iter.Next() {
+		fl := flagName{bits: flagBits(iter.Key()), name: iter.Value().String()}
+		if fl.bits == 0 {
+			panic(fmt.Errorf("flags %s has a zero value for %q", ft, fl.name))
+		}
+		if all&fl.bits != 0 {
+			panic(fmt.Errorf("flags %s has overlapping values, including %q", ft, fl.name))
+		}
+		if _, dup := values[fl.name]; dup {
+			panic(fmt.Errorf("flags %s uses the name %q more than once", ft, fl.name))
+		}
+		all |= fl.bits
+		values[fl.name] = fl.bits
+		flags = append(flags, fl)
+	}
+
+	RegisterCodec(ft,
+		func(v interface{}) (types.AttributeValue, error) {
+			bits := flagBits(reflect.ValueOf(v))
+			if bits&^all != 0 {
+				return nil, fmt.Errorf("%s value %#x has bits %#x that are not known flags", ft, bits, bits&^all)
+			}
+			var ss []string
+			for _, fl := range flags {
+				if bits&fl.bits != 0 {
+					ss = append(ss, fl.name)
+				}
+			}
+			if len(ss) == 0 {
+				return nil, errEmptySet
+			}
+			return newStringSet(ss), nil
+		},
+		func(av types.AttributeValue) (interface{}, error) {
+			ss, ok := av.(*types.AttributeValueMemberSS)
+			if !ok {
+				return nil, fmt.Errorf("flags %s must be decoded from a string set, not %s", ft, attrValString(av))
+			}
+			var bits uint64
+			for _, n := range ss.Value {
+				b, ok := values[n]
+				if !ok {
+					return nil, fmt.Errorf("%q is not a known flag of %s", n, ft)
+				}
+				bits |= b
+			}
+			v := reflect.New(ft).Elem()
+			if v.CanUint() {
+				v.SetUint(bits)
+			} else {
+				v.SetInt(int64(bits))
+			}
+			return v.Interface(), nil
+		})
+}
+
+type flagName struct {
+	bits uint64
+	name string
+}
+
+// flagBits returns the bits of v, an integer of any kind.
+func flagBits(v reflect.Value) uint64 {
+	if v.CanUint() {
+		return v.Uint()
+	}
+	return uint64(v.Int())
+}
@@ -0,0 +1,119 @@
+package ddbstruct
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+type enumStatus int
+
+const (
+	enumActive enumStatus = iota + 1
+	enumClosed
+)
+
+func registerEnumStatus() {
+	RegisterEnum(map[enumStatus]string{enumActive: "ACTIVE", enumClosed: "CLOSED"})
+}
+
+func TestEnumRoundTrip(t *testing.T) {
+	type z struct {
+		S enumStatus
+		P *enumStatus
+		L []enumStatus
+	}
+	registerEnumStatus()
+	c := enumClosed
+	in := &z{S: enumActive, P: &c, L: []enumStatus{enumClosed, enumActive}}
+	m, err := cache.get(in).encode(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := m["S"].(*types.AttributeValueMemberS).Value; s != "ACTIVE" {
+		t.Fatalf("expected \"ACTIVE\", got %q", s)
+	}
+	if s := m["P"].(*types.AttributeValueMemberS).Value; s != "CLOSED" {
+		t.Fatalf("expected \"CLOSED\", got %q", s)
+	}
+	out := &z{}
+	if err = cache.get(out).decode(out, m, false); err != nil {
+		t.Fatal(err)
+	}
+	if out.S != in.S || out.P == nil || *out.P != c || len(out.L) != 2 || out.L[0] != enumClosed || out.L[1] != enumActive {
+		t.Fatalf("expected %+v, got %+v", in, out)
+	}
+}
+
+func TestEnumUnknown(t *testing.T) {
+	type z struct{ S enumStatus }
+	registerEnumStatus()
+	in := &z{S: 7}
+	expectPanic(t, func() { cache.get(in).encode(in) })
+	out := &z{}
+	expectPanic(t, func() {
+		cache.get(out).decode(out, avmap{"S": &types.AttributeValueMemberS{Value: "PENDING"}}, false)
+	})
+	expectPanic(t, func() {
+		cache.get(out).decode(out, avmap{"S": &types.AttributeValueMemberN{Value: "1"}}, false)
+	})
+}
+
+func TestRegisterEnumInvalid(t *testing.T) {
+	expectPanic(t, func() { RegisterEnum(map[string]string{"a": "A"}) })
+	expectPanic(t, func() { RegisterEnum([]string{"A"}) })
+	expectPanic(t, func() { RegisterEnum(map[enumStatus]string{enumActive: "SAME", enumClosed: "SAME"}) })
+}
+
+type enumPerms uint32
+
+const (
+	permRead enumPerms = 1 << iota
+	permWrite
+	permAdmin
+)
+
+func registerEnumPerms() {
+	RegisterFlags(map[enumPerms]string{permRead: "READ", permWrite: "WRITE", permAdmin: "ADMIN"})
+}
+
+func TestFlagsRoundTrip(t *testing.T) {
+	type z struct {
+		P enumPerms
+		O enumPerms `ddb:"opt"`
+	}
+	registerEnumPerms()
+	in := &z{P: permRead | permAdmin}
+	m, err := cache.get(in).encode(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compareSlice(t, []string{"ADMIN", "READ"}, m["P"].(*types.AttributeValueMemberSS).Value)
+	if _, ok := m["O"]; ok {
+		t.Fatal("expected empty opt flags to be omitted")
+	}
+	out := &z{}
+	if err = cache.get(out).decode(out, m, false); err != nil {
+		t.Fatal(err)
+	}
+	if out.P != in.P {
+		t.Fatalf("expected %#x, got %#x", in.P, out.P)
+	}
+}
+
+func TestFlagsInvalid(t *testing.T) {
+	type z struct{ P enumPerms }
+	registerEnumPerms()
+	expectPanic(t, func() { in := &z{}; cache.get(in).encode(in) })
+	expectPanic(t, func() { in := &z{P: permRead | 1<<7}; cache.get(in).encode(in) })
+	out := &z{}
+	expectPanic(t, func() {
+		cache.get(out).decode(out, avmap{"P": &types.AttributeValueMemberSS{Value: []string{"READ", "EXEC"}}}, false)
+	})
+	expectPanic(t, func() {
+		cache.get(out).decode(out, avmap{"P": &types.AttributeValueMemberN{Value: "3"}}, false)
+	})
+	expectPanic(t, func() { RegisterFlags(map[enumPerms]string{0: "NONE"}) })
+	expectPanic(t, func() { RegisterFlags(map[enumPerms]string{1: "A", 3: "AB"}) })
+	expectPanic(t, func() { RegisterFlags(map[enumPerms]string{1: "A", 2: "A"}) })
+}
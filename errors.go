@@ -3,6 +3,7 @@ package ddbstruct
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	ddbtype "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 )
@@ -90,14 +91,34 @@ func (e *NoItemError) Error() string {
 	if len(e.Key) == 0 {
 		return "no item found (empty key)"
 	}
+	return "no item found matching key " + keyString(e.Key)
+}
 
+func keyString(key avmap) string {
 	var buf strings.Builder
-	for k := range e.Key {
+	for k := range key {
 		if buf.Len() > 0 {
 			buf.WriteByte(',')
 		}
-		fmt.Fprintf(&buf, "%q=%s", k, attrValString(e.Key[k]))
+		fmt.Fprintf(&buf, "%q=%s", k, attrValString(key[k]))
 	}
+	return buf.String()
+}
+
+// ExpiredItemError is returned by Get when the item was found, but its TTL
+// attribute is in the past. DynamoDB can take a while to delete expired items,
+// so they may still be read for some time after they expire. It unwraps to a
+// *NoItemError, so callers that only care whether there is a live item can
+// check for that.
+type ExpiredItemError struct {
+	Key     avmap
+	Expired time.Time
+}
+
+func (e *ExpiredItemError) Error() string {
+	return fmt.Sprintf("item matching key %s expired at %s", keyString(e.Key), e.Expired.UTC().Format(time.RFC3339))
+}
 
-	return "no item found matching key " + buf.String()
+func (e *ExpiredItemError) Unwrap() error {
+	return &NoItemError{Key: e.Key}
 }
@@ -0,0 +1,31 @@
+package ddbstruct
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+func TestExpiredItemError(t *testing.T) {
+	key := avmap{"pk": &types.AttributeValueMemberS{Value: "a"}}
+	err := fmt.Errorf("wrapped: %w", &ExpiredItemError{Key: key, Expired: time.Unix(1609556645, 0)})
+
+	var expired *ExpiredItemError
+	if !errors.As(err, &expired) {
+		t.Fatal("expected an ExpiredItemError")
+	}
+	var noItem *NoItemError
+	if !errors.As(err, &noItem) {
+		t.Fatal("expected ExpiredItemError to unwrap to a NoItemError")
+	}
+	if noItem.Key["pk"] == nil {
+		t.Fatalf("expected key to be carried over, got %v", noItem.Key)
+	}
+	if !strings.Contains(err.Error(), `"pk"="a"`) || !strings.Contains(err.Error(), "expired at 2021-01-02T03:04:05Z") {
+		t.Fatalf("unexpected message %q", err)
+	}
+}
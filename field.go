@@ -1,8 +1,15 @@
 package ddbstruct
 
 import (
+	"encoding"
 	"fmt"
 	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unsafe"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 )
 
 type field struct {
@@ -10,8 +17,17 @@ type field struct {
 	sk       bool
 	name     string
 	idx      int
-	defvalue string // only valid for string typed fields
+	via      []int // index path to the embedded struct holding this field, if any
+	defvalue string
+	defbox   interface{} // defvalue, parsed into field 0 of a boxType struct
+	gen      string      // generator that fills in a zero value on Put
+	created  bool        // set to the current time on Put, if the item doesn't exist yet
+	updated  bool        // set to the current time on every Put
+	ttl      bool        // the item's expiry time, for DynamoDB's TTL feature
 	optional bool
+	null     bool // nil values are written as NULL rather than as their zero value
+	utc      bool // times are converted to UTC before they're formatted
+	set      bool // encoded as SS, NS or BS, which can't be empty
 	enctype  string
 	gotype   reflect.Type
 	enc      encodeFunc
@@ -22,15 +38,156 @@ func (f *field) appendAV(m avmap, d interface{}) error {
 	if f.enc == nil {
 		return fmt.Errorf("no encode function available for field %q of %T", f.name, d)
 	}
-	if reflect.ValueOf(d).Elem().Field(f.idx).IsZero() {
+	d, err := f.container(d, false)
+	if err != nil {
+		return err
+	}
+	if d == nil {
+		return nil // behind a nil embedded pointer, so omitted, as encoding/json does
+	}
+	fv := reflect.ValueOf(d).Elem().Field(f.idx)
+	if f.null && isNil(fv) {
+		m[f.name] = &types.AttributeValueMemberNULL{Value: true}
+		return nil
+	}
+	if fv.IsZero() || (f.set && fv.Len() == 0) {
 		if f.optional { // skip zero attribute
 			return nil
 		}
-		if f.defvalue != "" { // apply default value
-			m[f.name] = f.enc(&struct{ S string }{S: f.defvalue}, 0)
+		if f.defbox != nil { // apply default value
+			m[f.name] = f.enc(f.defbox, 0)
 			return nil
 		}
 	}
 	m[f.name] = f.enc(d, f.idx)
 	return nil
 }
+
+// container returns a pointer to the struct that directly holds f, following
+// f.via through any embedded structs in d. If alloc is set, nil embedded
+// pointers are allocated along the way, which fails for an embedded pointer
+// of an unexported type, since it can't be set; otherwise container returns
+// nil if f is behind a nil embedded pointer, leaving d untouched.
+func (f *field) container(d interface{}, alloc bool) (interface{}, error) {
+	if len(f.via) == 0 {
+		return d, nil
+	}
+	v := reflect.ValueOf(d).Elem()
+	for _, idx := range f.via {
+		v = v.Field(idx)
+		if v.Kind() != reflect.Pointer {
+			continue
+		}
+		if v.IsNil() {
+			if !alloc {
+				return nil, nil
+			}
+			if !v.CanSet() {
+				return nil, fmt.Errorf("cannot allocate embedded pointer to unexported type %s for field %q", v.Type().Elem(), f.name)
+			}
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	if !v.CanInterface() {
+		// an embedded struct of an unexported type: its exported fields can still
+		// be set, as encoding/json does, but the struct can't be handed out through
+		// reflect without going around the export check
+		return reflect.NewAt(v.Type(), unsafe.Pointer(v.UnsafeAddr())).Interface(), nil
+	}
+	return v.Addr().Interface(), nil
+}
+
+// path describes where f is found within t, for error messages.
+func (f *field) path(t reflect.Type) string {
+	var buf strings.Builder
+	for _, idx := range f.via {
+		sf := t.Field(idx)
+		buf.WriteString(sf.Name)
+		buf.WriteByte('.')
+		t = sf.Type
+		if t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+	}
+	buf.WriteString(t.Field(f.idx).Name)
+	return buf.String()
+}
+
+// defcalc parses f.defvalue as a value of the field's type, once, so that it
+// can be encoded in place of a zero value. It must be called after typecalc.
+func (f *field) defcalc() (err error) {
+	b := reflect.New(boxType(f.gotype))
+	v := b.Elem().Field(0)
+	if v.Kind() == reflect.Pointer {
+		v.Set(reflect.New(v.Type().Elem()))
+		v = v.Elem()
+	}
+	if err = parseDefault(v, f.defvalue); err != nil {
+		return fmt.Errorf("field %q has invalid default value %q: %w", f.name, f.defvalue, err)
+	}
+
+	// make sure the default can be encoded too, rather than finding out on Put
+	defer func() {
+		if panicVal := recover(); panicVal != nil {
+			err = fmt.Errorf("field %q has default value %q that cannot be encoded: %v", f.name, f.defvalue, panicVal)
+		}
+	}()
+	f.enc(b.Interface(), 0)
+	f.defbox = b.Interface()
+	return nil
+}
+
+// parseDefault sets v from the string s.
+func parseDefault(v reflect.Value, s string) error {
+	switch v.Type() {
+	case typeDuration:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	case typeTime:
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	}
+	if isTextEncoder(v.Type()) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(i)
+	case reflect.Float32, reflect.Float64:
+		fv, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(fv)
+	default:
+		return fmt.Errorf("type %s does not support a default value", v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package ddbstruct
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+func TestNullRoundTrip(t *testing.T) {
+	type z struct {
+		S *string          `ddb:"null"`
+		L []int64          `ddb:"null"`
+		M map[string]int64 `ddb:"null"`
+	}
+	in := &z{}
+	md := cache.get(in)
+	m, err := md.encode(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, n := range []string{"S", "L", "M"} {
+		expectT(t, new(types.AttributeValueMemberNULL), m[n])
+	}
+	empty := ""
+	out := &z{S: &empty, L: []int64{1}, M: map[string]int64{}}
+	if err = md.decode(out, m, false); err != nil {
+		t.Fatal(err)
+	}
+	if out.S != nil || out.L != nil || out.M != nil {
+		t.Fatalf("expected nil fields, got %+v", out)
+	}
+}
+
+func TestNullEmptyIsNotNil(t *testing.T) {
+	type z struct {
+		S *string `ddb:"null"`
+		L []int64 `ddb:"null"`
+	}
+	empty := ""
+	in := &z{S: &empty, L: []int64{}}
+	md := cache.get(in)
+	m, err := md.encode(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectT(t, new(types.AttributeValueMemberS), m["S"])
+	expectT(t, new(types.AttributeValueMemberL), m["L"])
+	out := &z{}
+	if err = md.decode(out, m, false); err != nil {
+		t.Fatal(err)
+	}
+	if out.S == nil || *out.S != "" || out.L == nil {
+		t.Fatalf("expected empty (not nil) fields, got %+v", out)
+	}
+}
+
+func TestNullDecodeWithoutTag(t *testing.T) {
+	type z struct {
+		S *string
+		I int64
+	}
+	orig := "stale"
+	out := &z{S: &orig}
+	md := cache.get(out)
+	err := md.decode(out, avmap{
+		"S": &types.AttributeValueMemberNULL{Value: true},
+		"I": &types.AttributeValueMemberN{Value: "1"},
+	}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.S != nil {
+		t.Fatalf("expected nil, got %q", *out.S)
+	}
+	expectPanic(t, func() {
+		md.decode(out, avmap{
+			"S": &types.AttributeValueMemberNULL{Value: true},
+			"I": &types.AttributeValueMemberNULL{Value: true},
+		}, false)
+	})
+}
+
+func TestNullListElements(t *testing.T) {
+	type z struct{ X []*string }
+	av := &types.AttributeValueMemberL{Value: []types.AttributeValue{
+		&types.AttributeValueMemberS{Value: "a"},
+		&types.AttributeValueMemberNULL{Value: true},
+	}}
+	out := &z{}
+	f := typecalcField(t, out, 0)
+	f.dec(out, 0, av)
+	if len(out.X) != 2 || out.X[0] == nil || *out.X[0] != "a" || out.X[1] != nil {
+		t.Fatalf("expected [a nil], got %v", out.X)
+	}
+}
+
+func TestNullTagInvalid(t *testing.T) {
+	type notNilable struct {
+		X string `ddb:"null"`
+	}
+	type withOpt struct {
+		X *string `ddb:"null,opt"`
+	}
+	type onKey struct {
+		X *string `ddb:"null,pk"`
+	}
+	expectPanic(t, func() { cache.get(&notNilable{}) })
+	expectPanic(t, func() { cache.get(&withOpt{}) })
+	expectPanic(t, func() { cache.get(&onKey{}) })
+}
+
+func TestDefaultValues(t *testing.T) {
+	type z struct {
+		S  string        `ddb:"def=none"`
+		I  int8          `ddb:"def=-5"`
+		U  *uint64       `ddb:"def=7"`
+		F  float32       `ddb:"def=1.5"`
+		B  bool          `ddb:"def=true"`
+		D  time.Duration `ddb:"t=epoch,def=90s"`
+		T  time.Time     `ddb:"t=epoch,def=2021-01-02T03:04:05Z"`
+		IP net.IP        `ddb:"def=192.0.2.1"`
+	}
+	in := &z{}
+	m, err := cache.get(in).encode(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if in.U != nil {
+		t.Fatal("default was written into the source struct")
+	}
+	for n, v := range map[string]string{
+		"S": `"none"`, "I": "-5", "U": "7", "F": "1.5", "B": "true", "D": "90", "T": "1609556645", "IP": `"192.0.2.1"`,
+	} {
+		if g := attrValString(m[n]); g != v {
+			t.Errorf("%s: expected %s, got %s", n, v, g)
+		}
+	}
+	in.I = 3
+	m, err = cache.get(in).encode(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g := attrValString(m["I"]); g != "3" {
+		t.Errorf("expected non-zero value 3, got %s", g)
+	}
+}
+
+func TestDefaultValuesInvalid(t *testing.T) {
+	type badInt struct {
+		X int8 `ddb:"def=300"`
+	}
+	type badBool struct {
+		X bool `ddb:"def=maybe"`
+	}
+	type badType struct {
+		X []string `ddb:"def=a"`
+	}
+	type badEncode struct {
+		X Decimal `ddb:"def=lots"`
+	}
+	expectPanic(t, func() { cache.get(&badInt{}) })
+	expectPanic(t, func() { cache.get(&badBool{}) })
+	expectPanic(t, func() { cache.get(&badType{}) })
+	expectPanic(t, func() { cache.get(&badEncode{}) })
+}
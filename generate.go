@@ -0,0 +1,159 @@
+package ddbstruct
+
+import (
+	"crypto/rand"
+	"encoding"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math/big"
+	"reflect"
+	"time"
+)
+
+// genClock and genEntropy are the sources for generated values (and for TTLs
+// given as a duration from now, and for checking expiry in Get). They are
+// replaced with SetGenerationSource.
+var genClock = time.Now
+var genEntropy io.Reader = rand.Reader
+
+// SetGenerationSource replaces the clock and the source of random bytes used
+// for generated values (gen=, created, updated), for TTLs given as a duration
+// and for deciding whether an item has expired in Get. It is meant for tests,
+// so that generated IDs and times are predictable: with a fixed clock and a
+// reader that returns the same bytes, the same IDs are generated. A nil clock
+// restores time.Now, and a nil entropy restores crypto/rand.Reader.
+//
+// SetGenerationSource is not safe to call while Get or Put may be running in
+// another goroutine.
+func SetGenerationSource(clock func() time.Time, entropy io.Reader) {
+	if clock == nil {
+		clock = time.Now
+	}
+	if entropy == nil {
+		entropy = rand.Reader
+	}
+	genClock, genEntropy = clock, entropy
+}
+
+// generators produce the string values for the gen= tag option, other than
+// gen=now, which produces a time.
+var generators = map[string]func() (string, error){
+	"uuid":  genUUID,
+	"ulid":  genULID,
+	"ksuid": genKSUID,
+}
+
+// gencalc checks that the field's gen= option, or its created or updated
+// flag, can produce a value of its type.
+func (f *field) gencalc() error {
+	t := indirectType(f.gotype)
+	if f.created || f.updated {
+		if f.created && f.updated {
+			return fmt.Errorf("field %q tagged as both created and updated", f.name)
+		}
+		if f.gen != "" {
+			return fmt.Errorf("field %q tagged with generator %q, but also as a created or updated time", f.name, f.gen)
+		}
+		if t != typeTime {
+			return fmt.Errorf("field %q tagged as a created or updated time, but type %s is not a time", f.name, f.gotype)
+		}
+		return nil
+	}
+	if f.gen == "now" {
+		if t != typeTime {
+			return fmt.Errorf("field %q tagged with gen=now, but type %s is not a time", f.name, f.gotype)
+		}
+		return nil
+	}
+	if _, ok := generators[f.gen]; !ok {
+		return fmt.Errorf("field %q tagged with unknown generator %q", f.name, f.gen)
+	}
+	if t.Kind() != reflect.String && !isTextEncoder(t) {
+		return fmt.Errorf("field %q tagged with gen=%s, but type %s cannot hold a string", f.name, f.gen, f.gotype)
+	}
+	return nil
+}
+
+// generate fills in the field within d with a new value, if it is zero. An
+// updated time is always replaced.
+func (f *field) generate(d interface{}) error {
+	c, err := f.container(d, true)
+	if err != nil {
+		return err
+	}
+	v := reflect.ValueOf(c).Elem().Field(f.idx)
+	if !v.IsZero() && !f.updated {
+		return nil
+	}
+	v = maybealloc(v).Elem()
+	if f.gen == "now" || f.created || f.updated {
+		v.Set(reflect.ValueOf(genClock()))
+		return nil
+	}
+	id, err := generators[f.gen]()
+	if err != nil {
+		return fmt.Errorf("cannot generate %s for field %q: %w", f.gen, f.name, err)
+	}
+	if isTextEncoder(v.Type()) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(id))
+	}
+	v.SetString(id)
+	return nil
+}
+
+// genUUID makes a random (version 4) UUID.
+func genUUID() (string, error) {
+	var b [16]byte
+	if _, err := io.ReadFull(genEntropy, b[:]); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40 // version 4
+	b[8] = b[8]&0x3f | 0x80 // RFC 4122 variant
+	h := hex.EncodeToString(b[:])
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:], nil
+}
+
+const crockfordBase32 = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// genULID makes a ULID: a 48 bit millisecond timestamp followed by 80 random
+// bits, written in Crockford's base32 so that ULIDs sort by creation time.
+func genULID() (string, error) {
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[:8], uint64(genClock().UnixMilli())<<16)
+	if _, err := io.ReadFull(genEntropy, b[6:]); err != nil {
+		return "", err
+	}
+	return encodeBase(b[:], crockfordBase32, 26), nil
+}
+
+const base62 = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// ksuidEpoch is the start of KSUID time, in Unix seconds.
+const ksuidEpoch = 1400000000
+
+// genKSUID makes a KSUID: a 32 bit timestamp in seconds since ksuidEpoch
+// followed by 128 random bits, written in base62.
+func genKSUID() (string, error) {
+	var b [20]byte
+	binary.BigEndian.PutUint32(b[:4], uint32(genClock().Unix()-ksuidEpoch))
+	if _, err := io.ReadFull(genEntropy, b[4:]); err != nil {
+		return "", err
+	}
+	return encodeBase(b[:], base62, 27), nil
+}
+
+// encodeBase writes b, as a big endian number, in the given alphabet, padded
+// with leading zero digits to width.
+func encodeBase(b []byte, alphabet string, width int) string {
+	n := new(big.Int).SetBytes(b)
+	base := big.NewInt(int64(len(alphabet)))
+	out := make([]byte, width)
+	for idx := width - 1; idx >= 0; idx-- {
+		var rem big.Int
+		n.DivMod(n, base, &rem)
+		out[idx] = alphabet[rem.Int64()]
+	}
+	return string(out)
+}
@@ -0,0 +1,265 @@
+package ddbstruct
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// fixGenerators makes generated values deterministic until the test ends.
+func fixGenerators(t *testing.T, now time.Time, entropy []byte) {
+	t.Helper()
+	SetGenerationSource(func() time.Time { return now }, bytes.NewReader(bytes.Repeat(entropy, 64)))
+	t.Cleanup(func() { SetGenerationSource(nil, nil) })
+}
+
+func TestSetGenerationSource(t *testing.T) {
+	type z struct {
+		ID      string    `ddb:"pk,gen=uuid"`
+		Created time.Time `ddb:"created"`
+	}
+	now := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	SetGenerationSource(func() time.Time { return now }, bytes.NewReader(bytes.Repeat([]byte{0}, 16)))
+	in := &z{}
+	err := cache.get(in).generate(in)
+	SetGenerationSource(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if in.ID != "00000000-0000-4000-8000-000000000000" || !in.Created.Equal(now) {
+		t.Fatalf("expected a predictable id and time, got %+v", in)
+	}
+	if genEntropy != rand.Reader || genClock().Before(now) {
+		t.Fatal("expected nil to restore the default sources")
+	}
+}
+
+func TestGenUUID(t *testing.T) {
+	fixGenerators(t, time.Now(), []byte{0xff})
+	id, err := genUUID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "ffffffff-ffff-4fff-bfff-ffffffffffff" {
+		t.Fatalf("unexpected uuid %s", id)
+	}
+}
+
+func TestGenULID(t *testing.T) {
+	// the example timestamp from the ULID specification
+	fixGenerators(t, time.UnixMilli(1469918176385), []byte{0})
+	id, err := genULID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "01ARYZ6S410000000000000000" {
+		t.Fatalf("unexpected ulid %s", id)
+	}
+	fixGenerators(t, time.UnixMilli(1469918176386), []byte{0})
+	later, err := genULID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if later <= id {
+		t.Fatalf("expected %s to sort after %s", later, id)
+	}
+}
+
+func TestGenKSUID(t *testing.T) {
+	fixGenerators(t, time.Unix(ksuidEpoch, 0), []byte{0})
+	id, err := genKSUID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "000000000000000000000000000" {
+		t.Fatalf("unexpected ksuid %s", id)
+	}
+	fixGenerators(t, time.Unix(ksuidEpoch+1, 0), []byte{0xff})
+	id, err = genKSUID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !regexp.MustCompile("^[0-9A-Za-z]{27}$").MatchString(id) {
+		t.Fatalf("unexpected ksuid %s", id)
+	}
+}
+
+type genID string
+
+func (g genID) MarshalText() ([]byte, error)  { return []byte(g), nil }
+func (g *genID) UnmarshalText(b []byte) error { *g = genID("id-" + string(b)); return nil }
+
+func TestGenerateFields(t *testing.T) {
+	type z struct {
+		ID      string     `ddb:"pk,gen=ulid"`
+		Request *string    `ddb:"gen=uuid"`
+		Trace   genID      `ddb:"gen=ksuid"`
+		At      time.Time  `ddb:"gen=now"`
+		Kept    string     `ddb:"gen=uuid"`
+		When    *time.Time `ddb:"gen=now"`
+	}
+	now := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	fixGenerators(t, now, []byte{0})
+	in := &z{Kept: "mine"}
+	if err := cache.get(in).generate(in); err != nil {
+		t.Fatal(err)
+	}
+	if in.ID == "" || in.Request == nil || *in.Request == "" || in.Trace[:3] != "id-" {
+		t.Fatalf("values not generated: %+v", in)
+	}
+	if !in.At.Equal(now) || in.When == nil || !in.When.Equal(now) {
+		t.Fatalf("expected time %v, got %+v", now, in)
+	}
+	if in.Kept != "mine" {
+		t.Fatalf("non-zero value was replaced with %q", in.Kept)
+	}
+}
+
+func TestGenerateInvalid(t *testing.T) {
+	type unknown struct {
+		X string `ddb:"gen=serial"`
+	}
+	type notString struct {
+		X int64 `ddb:"gen=uuid"`
+	}
+	type notTime struct {
+		X string `ddb:"gen=now"`
+	}
+	type withDefault struct {
+		X net.IP `ddb:"gen=uuid,def=::1"`
+	}
+	expectPanic(t, func() { cache.get(&unknown{}) })
+	expectPanic(t, func() { cache.get(&notString{}) })
+	expectPanic(t, func() { cache.get(&notTime{}) })
+	expectPanic(t, func() { cache.get(&withDefault{}) })
+}
+
+func TestCreatedUpdatedStamps(t *testing.T) {
+	type z struct {
+		ID      string    `ddb:"pk"`
+		Created time.Time `ddb:"created,t=epoch"`
+		Updated time.Time `ddb:"updated,t=nano"`
+	}
+	now := time.Date(2021, 1, 2, 3, 4, 5, 6, time.UTC)
+	fixGenerators(t, now, []byte{0})
+	earlier := now.Add(-time.Hour)
+	in := &z{ID: "a", Updated: earlier}
+	md := cache.get(in)
+	if err := md.generate(in); err != nil {
+		t.Fatal(err)
+	}
+	if !in.Created.Equal(now) || !in.Updated.Equal(now) {
+		t.Fatalf("expected both times to be %v, got %+v", now, in)
+	}
+	in.Created = earlier
+	if err := md.generate(in); err != nil {
+		t.Fatal(err)
+	}
+	if !in.Created.Equal(earlier) {
+		t.Fatalf("created time was replaced: %v", in.Created)
+	}
+	m, err := md.encode(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g := attrValString(m["Created"]); g != "1609553045" {
+		t.Fatalf("expected created in epoch seconds, got %s", g)
+	}
+	if g := attrValString(m["Updated"]); g != "1609556645000000006" {
+		t.Fatalf("expected updated in nanoseconds, got %s", g)
+	}
+}
+
+func TestCreatedPutConditions(t *testing.T) {
+	type z struct {
+		ID      string    `ddb:"pk"`
+		Sort    int64     `ddb:"sk"`
+		Name    string    `ddb:"n=name"`
+		Created time.Time `ddb:"n=created,created,t=epoch"`
+	}
+	in := &z{ID: "a", Sort: 1, Name: "thing", Created: time.Unix(2000, 0)}
+	md := cache.get(in)
+	m, err := md.encode(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := md.key(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(key) != 2 || key["ID"] == nil || key["Sort"] == nil {
+		t.Fatalf("unexpected key %v", key)
+	}
+	table := "table"
+	put := &dynamodb.PutItemInput{TableName: &table, Item: m}
+
+	p := md.putIfNew(put)
+	if e := "attribute_not_exists(#k)"; *p.ConditionExpression != e || p.ExpressionAttributeNames["#k"] != "ID" {
+		t.Fatalf("expected %q on ID, got %q on %v", e, *p.ConditionExpression, p.ExpressionAttributeNames)
+	}
+	if put.ConditionExpression != nil {
+		t.Fatal("putIfNew modified the original request")
+	}
+
+	old := &types.AttributeValueMemberN{Value: "1000"}
+	p = md.putKeepCreated(put, old)
+	if e := "attribute_exists(#k) AND #c = :c"; *p.ConditionExpression != e {
+		t.Fatalf("expected %q, got %q", e, *p.ConditionExpression)
+	}
+	if p.ExpressionAttributeNames["#c"] != "created" || p.ExpressionAttributeValues[":c"] != old {
+		t.Fatalf("unexpected condition names %v and values %v", p.ExpressionAttributeNames, p.ExpressionAttributeValues)
+	}
+	if p.Item["created"] != old || p.Item["name"] != m["name"] {
+		t.Fatalf("expected the stored created time with the new attributes, got %v", p.Item)
+	}
+	if g := attrValString(m["created"]); g != "2000" {
+		t.Fatalf("putKeepCreated modified the original item, created is %s", g)
+	}
+
+	for _, stored := range []types.AttributeValue{nil, &types.AttributeValueMemberNULL{Value: true}} {
+		p = md.putKeepCreated(put, stored)
+		if e := "attribute_exists(#k) AND (attribute_not_exists(#c) OR attribute_type(#c, :null))"; *p.ConditionExpression != e {
+			t.Fatalf("expected %q, got %q", e, *p.ConditionExpression)
+		}
+		if p.Item["created"] != m["created"] {
+			t.Fatalf("expected the item's own created time, got %v", p.Item)
+		}
+	}
+}
+
+func TestCreatedUpdatedInvalid(t *testing.T) {
+	type notTime struct {
+		X string `ddb:"updated"`
+	}
+	type both struct {
+		X time.Time `ddb:"created,updated"`
+	}
+	type twice struct {
+		X time.Time `ddb:"created"`
+		Y time.Time `ddb:"created"`
+	}
+	type withGen struct {
+		X time.Time `ddb:"created,gen=now"`
+	}
+	expectPanic(t, func() { cache.get(&notTime{}) })
+	expectPanic(t, func() { cache.get(&both{}) })
+	expectPanic(t, func() { cache.get(&twice{}) })
+	expectPanic(t, func() { cache.get(&withGen{}) })
+}
+
+func TestIsConditionFailed(t *testing.T) {
+	if !isConditionFailed(fmt.Errorf("operation error: %w", &types.ConditionalCheckFailedException{})) {
+		t.Fatal("expected a wrapped ConditionalCheckFailedException to be recognised")
+	}
+	if isConditionFailed(nil) || isConditionFailed(errors.New("other")) {
+		t.Fatal("expected other errors not to be recognised")
+	}
+}
@@ -0,0 +1,117 @@
+package ddbstruct
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// isAnyType reports whether t is an empty interface, which can hold any value.
+func isAnyType(t reflect.Type) bool {
+	return t.Kind() == reflect.Interface && t.NumMethod() == 0
+}
+
+// encAny encodes whatever value an interface{} field holds, picking the
+// encoding from its dynamic type.
+func encAny(s interface{}, f int) types.AttributeValue {
+	return anyToAttr(readF(s, f).Interface())
+}
+
+// decAny decodes an attribute into the natural Go value for it: a string,
+// json.Number, bool, []byte, nil, []interface{} or map[string]interface{}, or
+// a slice of strings, json.Numbers or []byte for the set types. Numbers are
+// json.Numbers, as with a json.Decoder using UseNumber, so that none lose
+// precision.
+func decAny(s interface{}, f int, av types.AttributeValue) {
+	d := getF(s, f)
+	if v := attrToAny(av); v != nil {
+		d.Set(reflect.ValueOf(v))
+		return
+	}
+	d.Set(reflect.Zero(d.Type()))
+}
+
+func anyToAttr(v interface{}) types.AttributeValue {
+	switch x := v.(type) {
+	case nil:
+		return &types.AttributeValueMemberNULL{Value: true}
+	case string:
+		return &types.AttributeValueMemberS{Value: x}
+	case bool:
+		return &types.AttributeValueMemberBOOL{Value: x}
+	case json.Number:
+		return newNumber(x.String())
+	case []byte:
+		return &types.AttributeValueMemberB{Value: x}
+	case []interface{}:
+		l := make([]types.AttributeValue, len(x))
+		for idx := range x {
+			l[idx] = anyToAttr(x[idx])
+		}
+		return &types.AttributeValueMemberL{Value: l}
+	case map[string]interface{}:
+		m := make(map[string]types.AttributeValue, len(x))
+		for k := range x {
+			m[k] = anyToAttr(x[k])
+		}
+		return &types.AttributeValueMemberM{Value: m}
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &types.AttributeValueMemberN{Value: strconv.FormatInt(rv.Int(), 10)}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &types.AttributeValueMemberN{Value: strconv.FormatUint(rv.Uint(), 10)}
+	case reflect.Float32:
+		return newNumber(strconv.FormatFloat(rv.Float(), 'G', -1, 32))
+	case reflect.Float64:
+		return newNumber(strconv.FormatFloat(rv.Float(), 'G', -1, 64))
+	}
+	// anything else is encoded as a field of its dynamic type would be
+	ec, err := newValueCodec(fmt.Sprintf("(%T)", v), rv.Type())
+	if err != nil {
+		panic(err)
+	}
+	return ec.encode(rv)
+}
+
+func attrToAny(av types.AttributeValue) interface{} {
+	switch v := av.(type) {
+	case *types.AttributeValueMemberNULL:
+		return nil
+	case *types.AttributeValueMemberS:
+		return v.Value
+	case *types.AttributeValueMemberN:
+		return json.Number(v.Value)
+	case *types.AttributeValueMemberBOOL:
+		return v.Value
+	case *types.AttributeValueMemberB:
+		return v.Value
+	case *types.AttributeValueMemberL:
+		l := make([]interface{}, len(v.Value))
+		for idx := range v.Value {
+			l[idx] = attrToAny(v.Value[idx])
+		}
+		return l
+	case *types.AttributeValueMemberM:
+		m := make(map[string]interface{}, len(v.Value))
+		for k := range v.Value {
+			m[k] = attrToAny(v.Value[k])
+		}
+		return m
+	case *types.AttributeValueMemberSS:
+		return append([]string(nil), v.Value...)
+	case *types.AttributeValueMemberNS:
+		ns := make([]json.Number, len(v.Value))
+		for idx := range v.Value {
+			ns[idx] = json.Number(v.Value[idx])
+		}
+		return ns
+	case *types.AttributeValueMemberBS:
+		return append([][]byte(nil), v.Value...)
+	}
+	panic(fmt.Errorf("cannot decode %s into an interface{}", attrValString(av)))
+}
@@ -0,0 +1,92 @@
+package ddbstruct
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+func TestAnyRoundTrip(t *testing.T) {
+	type z struct {
+		A     interface{}
+		Attrs map[string]interface{}
+	}
+	in := &z{
+		A: []interface{}{"s", json.Number("1.5"), true, nil},
+		Attrs: map[string]interface{}{
+			"name":  "x",
+			"count": json.Number("12345678901234567890123"),
+			"raw":   []byte{1, 2},
+			"doc":   map[string]interface{}{"ok": false},
+		},
+	}
+	m, err := cache.get(in).encode(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectT(t, new(types.AttributeValueMemberL), m["A"])
+	expectT(t, new(types.AttributeValueMemberM), m["Attrs"])
+	out := &z{}
+	if err = cache.get(out).decode(out, m, false); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Fatalf("expected %#v, got %#v", in, out)
+	}
+}
+
+func TestAnyLargeNumber(t *testing.T) {
+	type z struct{ A interface{} }
+	in := &z{A: json.Number("12345678901234567890123")}
+	av := encAny(in, 0)
+	out := &z{}
+	decAny(out, 0, av)
+	if out.A != in.A {
+		t.Fatalf("expected %#v, got %#v", in.A, out.A)
+	}
+	decAny(out, 0, &types.AttributeValueMemberN{Value: "3"})
+	if out.A != json.Number("3") {
+		t.Fatalf("expected json.Number(\"3\"), got %#v", out.A)
+	}
+}
+
+func TestAnyEncodeDynamic(t *testing.T) {
+	type z struct{ A interface{} }
+	for _, tc := range []struct {
+		v   interface{}
+		exp types.AttributeValue
+	}{
+		{nil, &types.AttributeValueMemberNULL{Value: true}},
+		{json.Number("12345678901234567890"), &types.AttributeValueMemberN{Value: "12345678901234567890"}},
+		{int8(-3), &types.AttributeValueMemberN{Value: "-3"}},
+		{uint64(7), &types.AttributeValueMemberN{Value: "7"}},
+		{float32(0.5), &types.AttributeValueMemberN{Value: "0.5"}},
+		{time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC), &types.AttributeValueMemberS{Value: "2021-01-02T03:04:05Z"}},
+	} {
+		av := encAny(&z{A: tc.v}, 0)
+		if !reflect.DeepEqual(av, tc.exp) {
+			t.Errorf("encoding %#v: expected %#v, got %#v", tc.v, tc.exp, av)
+		}
+	}
+	expectPanic(t, func() { encAny(&z{A: make(chan int)}, 0) })
+}
+
+func TestAnyDecodeSets(t *testing.T) {
+	type z struct{ A interface{} }
+	out := &z{}
+	decAny(out, 0, &types.AttributeValueMemberSS{Value: []string{"a", "b"}})
+	compareSlice(t, []string{"a", "b"}, out.A)
+	decAny(out, 0, &types.AttributeValueMemberNS{Value: []string{"1", "2.5"}})
+	compareSlice(t, []json.Number{"1", "2.5"}, out.A)
+	decAny(out, 0, &types.AttributeValueMemberBS{Value: [][]byte{{1}}})
+	if !reflect.DeepEqual(out.A, [][]byte{{1}}) {
+		t.Fatalf("expected [][]byte{{1}}, got %#v", out.A)
+	}
+	decAny(out, 0, &types.AttributeValueMemberNULL{Value: true})
+	if out.A != nil {
+		t.Fatalf("expected nil, got %#v", out.A)
+	}
+}
@@ -0,0 +1,71 @@
+package ddbstruct
+
+import (
+	"reflect"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// AttributeMarshaler is implemented by types that encode themselves as a
+// DynamoDB attribute of any kind. It takes precedence over every other
+// encoding ddbstruct would otherwise guess for the type.
+type AttributeMarshaler interface {
+	MarshalDynamoDBAttribute() (types.AttributeValue, error)
+}
+
+// AttributeUnmarshaler is implemented by types that decode themselves from a
+// DynamoDB attribute. A type must implement both AttributeMarshaler and
+// AttributeUnmarshaler (either on the value or the pointer) to be used.
+type AttributeUnmarshaler interface {
+	UnmarshalDynamoDBAttribute(types.AttributeValue) error
+}
+
+var intfAttributeMarshaler = reflect.TypeOf(new(AttributeMarshaler)).Elem()
+var intfAttributeUnmarshaler = reflect.TypeOf(new(AttributeUnmarshaler)).Elem()
+
+func encAttribute(s interface{}, f int) types.AttributeValue {
+	fp := readF(s, f)
+	enc, ok := fp.Interface().(AttributeMarshaler)
+	if !ok {
+		enc, ok = fp.Addr().Interface().(AttributeMarshaler)
+		if !ok {
+			panic("neither " + fp.Type().String() + " nor *" + fp.Type().String() + " implements MarshalDynamoDBAttribute")
+		}
+	}
+
+	av, err := enc.MarshalDynamoDBAttribute()
+	if err != nil {
+		panic(err)
+	}
+	return av
+}
+
+func decAttribute(s interface{}, f int, av types.AttributeValue) {
+	fp := getF(s, f)
+	dec, ok := fp.Interface().(AttributeUnmarshaler)
+	if !ok {
+		dec, ok = fp.Addr().Interface().(AttributeUnmarshaler)
+		if !ok {
+			panic("neither " + fp.Type().String() + " nor *" + fp.Type().String() + " implements UnmarshalDynamoDBAttribute")
+		}
+	}
+
+	err := dec.UnmarshalDynamoDBAttribute(av)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func isAttributeEncoder(t reflect.Type) bool {
+	var e, d bool
+	e = t.Implements(intfAttributeMarshaler)
+	d = t.Implements(intfAttributeUnmarshaler)
+
+	// if the type isn't already a pointer, check to see if a pointer to
+	// the type satisfies the interface instead
+	if t.Kind() != reflect.Pointer {
+		e = e || reflect.PointerTo(t).Implements(intfAttributeMarshaler)
+		d = d || reflect.PointerTo(t).Implements(intfAttributeUnmarshaler)
+	}
+	return e && d
+}
@@ -0,0 +1,81 @@
+package ddbstruct
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// attrCents is an int64, which would normally be guessed as a plain number,
+// but encodes itself as a map instead.
+type attrCents int64
+
+func (c attrCents) MarshalDynamoDBAttribute() (types.AttributeValue, error) {
+	return &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+		"cents": &types.AttributeValueMemberN{Value: strconv.FormatInt(int64(c), 10)},
+	}}, nil
+}
+
+func (c *attrCents) UnmarshalDynamoDBAttribute(av types.AttributeValue) error {
+	m, ok := av.(*types.AttributeValueMemberM)
+	if !ok {
+		return errors.New("expected a map")
+	}
+	n, ok := m.Value["cents"].(*types.AttributeValueMemberN)
+	if !ok {
+		return errors.New("expected a cents number")
+	}
+	v, err := strconv.ParseInt(n.Value, 10, 64)
+	*c = attrCents(v)
+	return err
+}
+
+func TestAttributeRoundTrip(t *testing.T) {
+	type z struct{ X attrCents }
+	in := &z{X: 1234}
+	f := typecalcField(t, in, 0)
+	av := f.enc(in, 0)
+	expectT(t, new(types.AttributeValueMemberM), av)
+	out := &z{}
+	f.dec(out, 0, av)
+	if in.X != out.X {
+		t.Fatalf("expected %d, got %d", in.X, out.X)
+	}
+}
+
+func TestAttributePtrRoundTrip(t *testing.T) {
+	type z struct{ X *attrCents }
+	orig := attrCents(-5)
+	in := &z{X: &orig}
+	f := typecalcField(t, in, 0)
+	av := f.enc(in, 0)
+	expectT(t, new(types.AttributeValueMemberM), av)
+	out := &z{}
+	f.dec(out, 0, av)
+	if out.X == nil {
+		t.Fatal("out is nil")
+	}
+	if *in.X != *out.X {
+		t.Fatalf("expected %d, got %d", *in.X, *out.X)
+	}
+}
+
+func TestAttributeListRoundTrip(t *testing.T) {
+	type z struct{ X []attrCents }
+	in := &z{X: []attrCents{1, 2}}
+	f := typecalcField(t, in, 0)
+	av := f.enc(in, 0).(*types.AttributeValueMemberL)
+	expectT(t, new(types.AttributeValueMemberM), av.Value[0])
+	out := &z{}
+	f.dec(out, 0, av)
+	compareSlice(t, in.X, out.X)
+}
+
+func TestAttributeDecodeError(t *testing.T) {
+	type z struct{ X attrCents }
+	out := &z{}
+	f := typecalcField(t, out, 0)
+	expectPanic(t, func() { f.dec(out, 0, &types.AttributeValueMemberS{Value: "12"}) })
+}
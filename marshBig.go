@@ -0,0 +1,119 @@
+package ddbstruct
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+var typeBigInt = reflect.TypeOf(big.Int{})
+var typeBigFloat = reflect.TypeOf(big.Float{})
+var typeBigRat = reflect.TypeOf(big.Rat{})
+var typeDecimal = reflect.TypeOf(Decimal(""))
+
+// bigFloatDecodePrec is the precision given to a big.Float that has none when
+// a number is decoded into it; it's enough for 38 decimal digits.
+const bigFloatDecodePrec = 128
+
+// isBigNumber reports whether t is one of the arbitrary-precision number types
+// that are encoded as N.
+func isBigNumber(t reflect.Type) bool {
+	switch indirectType(t) {
+	case typeBigInt, typeBigFloat, typeBigRat, typeDecimal:
+		return true
+	}
+	return false
+}
+
+// newNumber checks that s can be stored by DynamoDB before encoding it.
+func newNumber(s string) *types.AttributeValueMemberN {
+	if err := checkNumber(s); err != nil {
+		panic(err)
+	}
+	return &types.AttributeValueMemberN{Value: s}
+}
+
+// parseRat parses any number DynamoDB may return, exactly.
+func parseRat(s string) *big.Rat {
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		panic(fmt.Errorf("cannot convert %q to a number", s))
+	}
+	return r
+}
+
+func encBigInt(s interface{}, f int) types.AttributeValue {
+	return newNumber(readF(s, f).Addr().Interface().(*big.Int).String())
+}
+func decBigInt(s interface{}, f int, av types.AttributeValue) {
+	sv := av.(*types.AttributeValueMemberN).Value
+	r := parseRat(sv)
+	if !r.IsInt() {
+		panic(fmt.Errorf("value %q is not an integer", sv))
+	}
+	getF(s, f).Addr().Interface().(*big.Int).Set(r.Num())
+}
+
+func encBigFloat(s interface{}, f int) types.AttributeValue {
+	d := readF(s, f).Addr().Interface().(*big.Float)
+	if d.IsInf() {
+		panic(fmt.Errorf("cannot encode infinite value %s", d))
+	}
+	return newNumber(strings.ToUpper(d.Text('g', -1)))
+}
+func decBigFloat(s interface{}, f int, av types.AttributeValue) {
+	d := getF(s, f).Addr().Interface().(*big.Float)
+	if d.Prec() == 0 {
+		d.SetPrec(bigFloatDecodePrec)
+	}
+	sv := av.(*types.AttributeValueMemberN).Value
+	if _, ok := d.SetString(sv); !ok {
+		panic(fmt.Errorf("cannot convert %q to %s", sv, typeBigFloat))
+	}
+}
+
+func encBigRat(s interface{}, f int) types.AttributeValue {
+	d := readF(s, f).Addr().Interface().(*big.Rat)
+	// a fraction has an exact decimal form only if its denominator has no
+	// prime factors other than 2 and 5
+	den := new(big.Int).Set(d.Denom())
+	var twos, fives int
+	two, five, rem := big.NewInt(2), big.NewInt(5), new(big.Int)
+	for q := new(big.Int); ; twos++ {
+		if q.QuoRem(den, two, rem); rem.Sign() != 0 {
+			break
+		}
+		den.Set(q)
+	}
+	for q := new(big.Int); ; fives++ {
+		if q.QuoRem(den, five, rem); rem.Sign() != 0 {
+			break
+		}
+		den.Set(q)
+	}
+	if den.Cmp(big.NewInt(1)) != 0 {
+		panic(fmt.Errorf("%s has no exact decimal representation", d))
+	}
+	places := twos
+	if fives > places {
+		places = fives
+	}
+	return newNumber(d.FloatString(places))
+}
+func decBigRat(s interface{}, f int, av types.AttributeValue) {
+	getF(s, f).Addr().Interface().(*big.Rat).Set(parseRat(av.(*types.AttributeValueMemberN).Value))
+}
+
+func encDecimal(s interface{}, f int) types.AttributeValue {
+	d := readF(s, f).String()
+	if d == "" {
+		d = "0"
+	}
+	return newNumber(d)
+}
+func decDecimal(s interface{}, f int, av types.AttributeValue) {
+	getF(s, f).SetString(av.(*types.AttributeValueMemberN).Value)
+}
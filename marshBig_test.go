@@ -0,0 +1,125 @@
+package ddbstruct
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+func TestBigIntRoundTrip(t *testing.T) {
+	type z struct{ X *big.Int }
+	n, _ := new(big.Int).SetString("-12345678901234567890123456789012345678", 10)
+	in := &z{X: n}
+	f := typecalcField(t, in, 0)
+	av := f.enc(in, 0)
+	expectT(t, new(types.AttributeValueMemberN), av)
+	out := &z{}
+	f.dec(out, 0, av)
+	if out.X == nil || out.X.Cmp(in.X) != 0 {
+		t.Fatalf("expected %s, got %s", in.X, out.X)
+	}
+}
+
+func TestBigIntTooPrecise(t *testing.T) {
+	type z struct{ X *big.Int }
+	n, _ := new(big.Int).SetString("123456789012345678901234567890123456789", 10)
+	in := &z{X: n}
+	f := typecalcField(t, in, 0)
+	expectPanic(t, func() { f.enc(in, 0) })
+}
+
+func TestBigIntNotInteger(t *testing.T) {
+	type z struct{ X big.Int }
+	out := &z{}
+	f := typecalcField(t, out, 0)
+	expectPanic(t, func() { f.dec(out, 0, &types.AttributeValueMemberN{Value: "1.5"}) })
+	f.dec(out, 0, &types.AttributeValueMemberN{Value: "1.5E+1"})
+	if out.X.Int64() != 15 {
+		t.Fatalf("expected 15, got %s", &out.X)
+	}
+}
+
+func TestBigFloatRoundTrip(t *testing.T) {
+	type z struct{ X *big.Float }
+	n, _ := new(big.Float).SetPrec(200).SetString("3.1415926535897932384626433832795028841")
+	in := &z{X: n}
+	f := typecalcField(t, in, 0)
+	av := f.enc(in, 0)
+	expectT(t, new(types.AttributeValueMemberN), av)
+	out := &z{}
+	f.dec(out, 0, av)
+	if out.X == nil || out.X.Text('g', 38) != in.X.Text('g', 38) {
+		t.Fatalf("expected %s, got %s", in.X.Text('g', 38), out.X.Text('g', 38))
+	}
+}
+
+func TestBigFloatOutOfRange(t *testing.T) {
+	type z struct{ X *big.Float }
+	in := &z{X: big.NewFloat(1e200)}
+	f := typecalcField(t, in, 0)
+	expectPanic(t, func() { f.enc(in, 0) })
+	in.X.SetInf(false)
+	expectPanic(t, func() { f.enc(in, 0) })
+}
+
+func TestBigRatRoundTrip(t *testing.T) {
+	type z struct{ X *big.Rat }
+	in := &z{X: big.NewRat(-1234, 8)}
+	f := typecalcField(t, in, 0)
+	av := f.enc(in, 0)
+	if n := av.(*types.AttributeValueMemberN).Value; n != "-154.25" {
+		t.Fatalf("expected -154.25, got %s", n)
+	}
+	out := &z{}
+	f.dec(out, 0, av)
+	if out.X == nil || out.X.Cmp(in.X) != 0 {
+		t.Fatalf("expected %s, got %s", in.X, out.X)
+	}
+}
+
+func TestBigRatInexact(t *testing.T) {
+	type z struct{ X *big.Rat }
+	in := &z{X: big.NewRat(1, 3)}
+	f := typecalcField(t, in, 0)
+	expectPanic(t, func() { f.enc(in, 0) })
+}
+
+func TestDecimalRoundTrip(t *testing.T) {
+	type z struct{ X Decimal }
+	in := &z{X: "99999999999999999999999999999999999.99"}
+	f := typecalcField(t, in, 0)
+	av := f.enc(in, 0)
+	expectT(t, new(types.AttributeValueMemberN), av)
+	out := &z{}
+	f.dec(out, 0, av)
+	if in.X != out.X {
+		t.Fatalf("expected %s, got %s", in.X, out.X)
+	}
+	in.X = ""
+	if n := f.enc(in, 0).(*types.AttributeValueMemberN).Value; n != "0" {
+		t.Fatalf("expected zero value to encode as 0, got %s", n)
+	}
+	in.X = "12 dollars"
+	expectPanic(t, func() { f.enc(in, 0) })
+}
+
+func TestDecimalSet(t *testing.T) {
+	type z struct {
+		X []Decimal `ddb:"t=set"`
+	}
+	in := &z{X: []Decimal{"10", "9.5", "10.0"}}
+	f := typecalcField(t, in, 0)
+	av := f.enc(in, 0)
+	expectT(t, new(types.AttributeValueMemberNS), av)
+	compareSlice(t, []string{"9.5", "10"}, av.(*types.AttributeValueMemberNS).Value)
+}
+
+func TestFloatOutOfRange(t *testing.T) {
+	type z struct{ X float64 }
+	for _, v := range []float64{1e200, 1e-200, math.NaN(), math.Inf(-1)} {
+		in := &z{X: v}
+		expectPanic(t, func() { encFloat(64)(in, 0) })
+	}
+}
@@ -11,7 +11,7 @@ var intfBinaryMarshaler = reflect.TypeOf(new(encoding.BinaryMarshaler)).Elem()
 var intfBinaryUnmarshaler = reflect.TypeOf(new(encoding.BinaryUnmarshaler)).Elem()
 
 func encBinary(s interface{}, f int) types.AttributeValue {
-	fp := getF(s, f)
+	fp := readF(s, f)
 	enc, ok := fp.Interface().(encoding.BinaryMarshaler)
 	if !ok {
 		enc, ok = fp.Addr().Interface().(encoding.BinaryMarshaler)
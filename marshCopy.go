@@ -1,26 +1,88 @@
 package ddbstruct
 
 import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 )
 
 func encString(s interface{}, f int) types.AttributeValue {
-	return &types.AttributeValueMemberS{Value: getF(s, f).String()}
+	return &types.AttributeValueMemberS{Value: readF(s, f).String()}
 }
 func decString(s interface{}, f int, av types.AttributeValue) {
 	getF(s, f).SetString(av.(*types.AttributeValueMemberS).Value)
 }
 
 func encBytes(s interface{}, f int) types.AttributeValue {
-	return &types.AttributeValueMemberB{Value: getF(s, f).Bytes()}
+	return &types.AttributeValueMemberB{Value: readF(s, f).Bytes()}
 }
 func decBytes(s interface{}, f int, av types.AttributeValue) {
 	getF(s, f).SetBytes(av.(*types.AttributeValueMemberB).Value)
 }
 
 func encBool(s interface{}, f int) types.AttributeValue {
-	return &types.AttributeValueMemberBOOL{Value: getF(s, f).Bool()}
+	return &types.AttributeValueMemberBOOL{Value: readF(s, f).Bool()}
 }
 func decBool(s interface{}, f int, av types.AttributeValue) {
 	getF(s, f).SetBool(av.(*types.AttributeValueMemberBOOL).Value)
 }
+
+// isByteSeq reports whether t is a slice or array of bytes.
+func isByteSeq(t reflect.Type) bool {
+	return (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && t.Elem().Kind() == reflect.Uint8
+}
+
+// byteSeq returns the contents of d, a slice or array of bytes.
+func byteSeq(d reflect.Value) []byte {
+	if d.Kind() == reflect.Slice {
+		return d.Bytes()
+	}
+	b := make([]byte, d.Len())
+	reflect.Copy(reflect.ValueOf(b), d)
+	return b
+}
+
+// setByteSeq sets d, a slice or array of bytes, to b. An array must be
+// exactly as long as b.
+func setByteSeq(d reflect.Value, b []byte) {
+	if d.Kind() == reflect.Slice {
+		d.SetBytes(b)
+		return
+	}
+	if len(b) != d.Len() {
+		panic(fmt.Errorf("cannot decode %d bytes into %s", len(b), d.Type()))
+	}
+	reflect.Copy(d, reflect.ValueOf(b))
+}
+
+func encByteArray(s interface{}, f int) types.AttributeValue {
+	return &types.AttributeValueMemberB{Value: byteSeq(readF(s, f))}
+}
+func decByteArray(s interface{}, f int, av types.AttributeValue) {
+	setByteSeq(getF(s, f), av.(*types.AttributeValueMemberB).Value)
+}
+
+func encHex(s interface{}, f int) types.AttributeValue {
+	return &types.AttributeValueMemberS{Value: hex.EncodeToString(byteSeq(readF(s, f)))}
+}
+func decHex(s interface{}, f int, av types.AttributeValue) {
+	b, err := hex.DecodeString(av.(*types.AttributeValueMemberS).Value)
+	if err != nil {
+		panic(err)
+	}
+	setByteSeq(getF(s, f), b)
+}
+
+func encBase64(s interface{}, f int) types.AttributeValue {
+	return &types.AttributeValueMemberS{Value: base64.StdEncoding.EncodeToString(byteSeq(readF(s, f)))}
+}
+func decBase64(s interface{}, f int, av types.AttributeValue) {
+	b, err := base64.StdEncoding.DecodeString(av.(*types.AttributeValueMemberS).Value)
+	if err != nil {
+		panic(err)
+	}
+	setByteSeq(getF(s, f), b)
+}
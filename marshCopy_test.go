@@ -149,3 +149,53 @@ func TestBoolPtrReuseRoundTrip(t *testing.T) {
 		t.Fatalf("expected %t, got %t", *in.X, *out.X)
 	}
 }
+
+func TestByteArrayRoundTrip(t *testing.T) {
+	type z struct {
+		X [4]byte
+		P *[2]byte
+	}
+	in := &z{X: [4]byte{1, 2, 3, 4}, P: &[2]byte{5, 6}}
+	m, err := cache.get(in).encode(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectT(t, new(types.AttributeValueMemberB), m["X"])
+	compareSlice(t, []byte{1, 2, 3, 4}, m["X"].(*types.AttributeValueMemberB).Value)
+	out := &z{}
+	if err = cache.get(out).decode(out, m, false); err != nil {
+		t.Fatal(err)
+	}
+	if out.X != in.X || out.P == nil || *out.P != *in.P {
+		t.Fatalf("expected %+v, got %+v", in, out)
+	}
+	expectPanic(t, func() { decByteArray(out, 0, &types.AttributeValueMemberB{Value: []byte{1, 2, 3}}) })
+}
+
+func TestByteTextRoundTrip(t *testing.T) {
+	type z struct {
+		H [3]byte `ddb:"t=hex"`
+		B []byte  `ddb:"t=base64"`
+	}
+	in := &z{H: [3]byte{0xde, 0xad, 0x01}, B: []byte("hi!")}
+	m, err := cache.get(in).encode(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := m["H"].(*types.AttributeValueMemberS).Value; s != "dead01" {
+		t.Fatalf("expected \"dead01\", got %q", s)
+	}
+	if s := m["B"].(*types.AttributeValueMemberS).Value; s != "aGkh" {
+		t.Fatalf("expected \"aGkh\", got %q", s)
+	}
+	out := &z{}
+	if err = cache.get(out).decode(out, m, false); err != nil {
+		t.Fatal(err)
+	}
+	if out.H != in.H || string(out.B) != string(in.B) {
+		t.Fatalf("expected %+v, got %+v", in, out)
+	}
+	expectPanic(t, func() { decHex(out, 0, &types.AttributeValueMemberS{Value: "dead"}) })
+	expectPanic(t, func() { decHex(out, 0, &types.AttributeValueMemberS{Value: "zz"}) })
+	expectPanic(t, func() { decBase64(out, 1, &types.AttributeValueMemberS{Value: "!"}) })
+}
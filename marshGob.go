@@ -0,0 +1,38 @@
+package ddbstruct
+
+import (
+	"bytes"
+	"encoding/gob"
+	"reflect"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// RegisterGobType records the concrete type of v with encoding/gob, so that
+// values of that type can be stored in interface-typed fields (or inside
+// them) encoded with t=gob. It is gob.Register, and should be called once for
+// each type, usually in an init function.
+func RegisterGobType(v interface{}) {
+	gob.Register(v)
+}
+
+// encGob stores a field as a self-contained gob stream. The field is encoded
+// through a pointer so that interface-typed fields keep their concrete type.
+func encGob(s interface{}, f int) types.AttributeValue {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(readF(s, f).Addr().Interface()); err != nil {
+		panic(err)
+	}
+	return &types.AttributeValueMemberB{Value: buf.Bytes()}
+}
+
+// decGob replaces the field with what was stored, starting from its zero value,
+// since gob leaves out zero fields and merges into existing maps.
+func decGob(s interface{}, f int, av types.AttributeValue) {
+	fp := getF(s, f)
+	fp.Set(reflect.Zero(fp.Type()))
+	dec := gob.NewDecoder(bytes.NewReader(av.(*types.AttributeValueMemberB).Value))
+	if err := dec.Decode(fp.Addr().Interface()); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,85 @@
+package ddbstruct
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+type gobShape interface{ Area() float64 }
+
+type gobSquare struct{ Side float64 }
+
+func (s gobSquare) Area() float64 { return s.Side * s.Side }
+
+func TestGobRoundTrip(t *testing.T) {
+	type inner struct {
+		Counts map[string]int
+		Tags   []string
+	}
+	type z struct {
+		X inner       `ddb:"t=gob"`
+		P *inner      `ddb:"t=gob"`
+		A interface{} `ddb:"t=gob"`
+		S gobShape    `ddb:"t=gob"`
+	}
+	RegisterGobType(gobSquare{})
+	in := &z{
+		X: inner{Counts: map[string]int{"a": 1}, Tags: []string{"x"}},
+		P: &inner{Tags: []string{"y"}},
+		A: gobSquare{Side: 2},
+		S: gobSquare{Side: 3},
+	}
+	m, err := cache.get(in).encode(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectT(t, new(types.AttributeValueMemberB), m["X"])
+	out := &z{}
+	if err = cache.get(out).decode(out, m, false); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Fatalf("expected %+v, got %+v", in, out)
+	}
+	if out.S.Area() != 9 {
+		t.Fatalf("expected area 9, got %v", out.S.Area())
+	}
+}
+
+func TestGobStaleDestination(t *testing.T) {
+	type inner struct {
+		N      int
+		S      string
+		Counts map[string]int
+	}
+	type z struct {
+		X inner  `ddb:"t=gob"`
+		P *inner `ddb:"t=gob"`
+	}
+	in := &z{X: inner{Counts: map[string]int{"new": 2}}, P: &inner{}}
+	m, err := cache.get(in).encode(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale := inner{N: 5, S: "stale", Counts: map[string]int{"old": 1}}
+	staleP := stale
+	staleP.Counts = map[string]int{"old": 1}
+	out := &z{X: stale, P: &staleP}
+	if err = cache.get(out).decode(out, m, false); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Fatalf("expected %+v, got %+v", in, out)
+	}
+}
+
+func TestGobUnregistered(t *testing.T) {
+	type unregistered struct{ N int }
+	type z struct {
+		A interface{} `ddb:"t=gob"`
+	}
+	expectPanic(t, func() { encGob(&z{A: unregistered{N: 1}}, 0) })
+	expectPanic(t, func() { decGob(&z{}, 0, &types.AttributeValueMemberB{Value: []byte("junk")}) })
+}
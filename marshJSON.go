@@ -1,7 +1,9 @@
 package ddbstruct
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"reflect"
 
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
@@ -11,7 +13,7 @@ var intfJSONMarshaler = reflect.TypeOf(new(json.Marshaler)).Elem()
 var intfJSONUnmarshaler = reflect.TypeOf(new(json.Unmarshaler)).Elem()
 
 func encJSON(s interface{}, f int) types.AttributeValue {
-	fp := getF(s, f)
+	fp := readF(s, f)
 	enc, ok := fp.Interface().(json.Marshaler)
 	if !ok {
 		enc, ok = fp.Addr().Interface().(json.Marshaler)
@@ -58,7 +60,7 @@ func isJSONEncoder(t reflect.Type) bool {
 }
 
 func encJSONRaw(s interface{}, f int) types.AttributeValue {
-	buf, err := json.Marshal(getF(s, f).Interface())
+	buf, err := json.Marshal(readF(s, f).Interface())
 	if err != nil {
 		panic(err)
 	}
@@ -75,3 +77,111 @@ func decJSONRaw(s interface{}, f int, av types.AttributeValue) {
 		panic(err)
 	}
 }
+
+// encJSONDoc stores the JSON form of a field as the equivalent native
+// attribute tree, so that its contents can be used in expressions.
+func encJSONDoc(s interface{}, f int) types.AttributeValue {
+	buf, err := json.Marshal(readF(s, f).Interface())
+	if err != nil {
+		panic(err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(buf))
+	dec.UseNumber() // keep numbers exactly as they were written
+	var doc interface{}
+	if err = dec.Decode(&doc); err != nil {
+		panic(err)
+	}
+	return docToAttr(doc)
+}
+
+func decJSONDoc(s interface{}, f int, av types.AttributeValue) {
+	buf, err := json.Marshal(attrToDoc(av))
+	if err != nil {
+		panic(err)
+	}
+	fp := getF(s, f)
+	if fp.Type().Kind() != reflect.Pointer {
+		fp = fp.Addr()
+	}
+	if err = json.Unmarshal(buf, fp.Interface()); err != nil {
+		panic(err)
+	}
+}
+
+// docToAttr converts a value decoded from JSON, with numbers as json.Number,
+// into an attribute.
+func docToAttr(doc interface{}) types.AttributeValue {
+	switch v := doc.(type) {
+	case nil:
+		return &types.AttributeValueMemberNULL{Value: true}
+	case bool:
+		return &types.AttributeValueMemberBOOL{Value: v}
+	case string:
+		return &types.AttributeValueMemberS{Value: v}
+	case json.Number:
+		return newNumber(v.String())
+	case []interface{}:
+		l := make([]types.AttributeValue, len(v))
+		for idx := range v {
+			l[idx] = docToAttr(v[idx])
+		}
+		return &types.AttributeValueMemberL{Value: l}
+	case map[string]interface{}:
+		m := make(map[string]types.AttributeValue, len(v))
+		for k := range v {
+			m[k] = docToAttr(v[k])
+		}
+		return &types.AttributeValueMemberM{Value: m}
+	}
+	panic(fmt.Errorf("cannot convert %T from a JSON document", doc))
+}
+
+// attrToDoc converts an attribute into the value json.Unmarshal would decode
+// from the same document, with numbers as json.Number. Sets, which JSON
+// doesn't have, become lists, and binary values become base64 strings, as
+// json.Marshal would write a []byte.
+func attrToDoc(av types.AttributeValue) interface{} {
+	switch v := av.(type) {
+	case *types.AttributeValueMemberNULL:
+		return nil
+	case *types.AttributeValueMemberBOOL:
+		return v.Value
+	case *types.AttributeValueMemberS:
+		return v.Value
+	case *types.AttributeValueMemberN:
+		return json.Number(v.Value)
+	case *types.AttributeValueMemberB:
+		return v.Value
+	case *types.AttributeValueMemberL:
+		l := make([]interface{}, len(v.Value))
+		for idx := range v.Value {
+			l[idx] = attrToDoc(v.Value[idx])
+		}
+		return l
+	case *types.AttributeValueMemberM:
+		m := make(map[string]interface{}, len(v.Value))
+		for k := range v.Value {
+			m[k] = attrToDoc(v.Value[k])
+		}
+		return m
+	case *types.AttributeValueMemberSS:
+		l := make([]interface{}, len(v.Value))
+		for idx := range v.Value {
+			l[idx] = v.Value[idx]
+		}
+		return l
+	case *types.AttributeValueMemberNS:
+		l := make([]interface{}, len(v.Value))
+		for idx := range v.Value {
+			l[idx] = json.Number(v.Value[idx])
+		}
+		return l
+	case *types.AttributeValueMemberBS:
+		l := make([]interface{}, len(v.Value))
+		for idx := range v.Value {
+			l[idx] = v.Value[idx]
+		}
+		return l
+	}
+	panic(fmt.Errorf("cannot convert %s to a JSON document", attrValString(av)))
+}
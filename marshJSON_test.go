@@ -1,7 +1,9 @@
 package ddbstruct
 
 import (
+	"encoding/json"
 	"math/big"
+	"reflect"
 	"testing"
 	"time"
 
@@ -99,3 +101,66 @@ func TestStructPtrJSONRawRoundTrip(t *testing.T) {
 		t.Fatalf("expected %+v, got %+v", *in.X, *out.X)
 	}
 }
+
+func TestJSONDocRoundTrip(t *testing.T) {
+	type doc struct {
+		Name  string            `json:"name"`
+		Big   json.Number       `json:"big"`
+		Tags  []string          `json:"tags"`
+		Attrs map[string]string `json:"attrs"`
+		Ok    bool              `json:"ok"`
+		Next  *doc              `json:"next"`
+	}
+	type z struct {
+		X doc `ddb:"t=doc"`
+	}
+	in := &z{X: doc{Name: "a", Big: "12345678901234567890.0123456789", Tags: []string{"x", "y"},
+		Attrs: map[string]string{"k": "v"}, Ok: true}}
+	m, err := cache.get(in).encode(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectT(t, new(types.AttributeValueMemberM), m["X"])
+	d := m["X"].(*types.AttributeValueMemberM).Value
+	if n := d["big"].(*types.AttributeValueMemberN).Value; n != string(in.X.Big) {
+		t.Fatalf("expected %s, got %s", in.X.Big, n)
+	}
+	expectT(t, new(types.AttributeValueMemberL), d["tags"])
+	expectT(t, new(types.AttributeValueMemberM), d["attrs"])
+	expectT(t, new(types.AttributeValueMemberBOOL), d["ok"])
+	expectT(t, new(types.AttributeValueMemberNULL), d["next"])
+	out := &z{}
+	if err = cache.get(out).decode(out, m, false); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Fatalf("expected %+v, got %+v", in, out)
+	}
+}
+
+func TestJSONDocSets(t *testing.T) {
+	type z struct {
+		X struct {
+			S []string  `json:"s"`
+			N []float64 `json:"n"`
+		} `ddb:"t=doc"`
+	}
+	m := avmap{"X": &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+		"s": &types.AttributeValueMemberSS{Value: []string{"a", "b"}},
+		"n": &types.AttributeValueMemberNS{Value: []string{"1.5", "2"}},
+	}}}
+	out := &z{}
+	if err := cache.get(out).decode(out, m, false); err != nil {
+		t.Fatal(err)
+	}
+	compareSlice(t, []string{"a", "b"}, out.X.S)
+	compareSlice(t, []float64{1.5, 2}, out.X.N)
+}
+
+func TestJSONDocBadNumber(t *testing.T) {
+	type z struct {
+		X json.RawMessage `ddb:"t=doc"`
+	}
+	in := &z{X: json.RawMessage(`{"n": 1e400}`)}
+	expectPanic(t, func() { encJSONDoc(in, 0) })
+}
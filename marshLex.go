@@ -0,0 +1,109 @@
+package ddbstruct
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// The lex encodings write numbers and times as fixed-width strings whose
+// lexicographic order matches their natural order, for use in string sort
+// keys. Numbers are mapped onto a uint64 that preserves their order, then
+// written as 20 zero-padded decimal digits.
+
+const lexDigits = 20 // the width of math.MaxUint64 in decimal
+
+// lexTimeLayout is RFC3339 with a fixed number of fractional digits, which
+// sorts correctly as long as every value is in UTC and in years 0 to 9999.
+const lexTimeLayout = "2006-01-02T15:04:05.000000000Z"
+
+const lexSignBit = uint64(1) << 63
+
+func lexString(u uint64) *types.AttributeValueMemberS {
+	return &types.AttributeValueMemberS{Value: fmt.Sprintf("%0*d", lexDigits, u)}
+}
+func lexParse(av types.AttributeValue) uint64 {
+	sv := av.(*types.AttributeValueMemberS).Value
+	if len(sv) != lexDigits {
+		panic(fmt.Errorf("%q is not a %d digit sortable number", sv, lexDigits))
+	}
+	u, err := strconv.ParseUint(sv, 10, 64)
+	if err != nil {
+		panic(fmt.Errorf("%q is not a sortable number: %w", sv, err))
+	}
+	return u
+}
+
+func encLexInt(s interface{}, f int) types.AttributeValue {
+	// flipping the sign bit moves negative numbers below positive ones
+	return lexString(uint64(readF(s, f).Int()) ^ lexSignBit)
+}
+func decLexInt(s interface{}, f int, av types.AttributeValue) {
+	d := getF(s, f)
+	i := int64(lexParse(av) ^ lexSignBit)
+	if d.OverflowInt(i) {
+		panic(fmt.Errorf("value %d overflows %s", i, d.Kind()))
+	}
+	d.SetInt(i)
+}
+
+func encLexUint(s interface{}, f int) types.AttributeValue {
+	return lexString(readF(s, f).Uint())
+}
+func decLexUint(s interface{}, f int, av types.AttributeValue) {
+	d := getF(s, f)
+	u := lexParse(av)
+	if d.OverflowUint(u) {
+		panic(fmt.Errorf("value %d overflows %s", u, d.Kind()))
+	}
+	d.SetUint(u)
+}
+
+func encLexFloat(s interface{}, f int) types.AttributeValue {
+	fv := readF(s, f).Float()
+	if math.IsNaN(fv) {
+		panic(fmt.Errorf("cannot encode NaN as a sortable number"))
+	}
+	// positive floats sort correctly by their bits once the sign bit is set;
+	// negative floats sort in reverse, so all of their bits are flipped
+	u := math.Float64bits(fv)
+	if u&lexSignBit != 0 {
+		u = ^u
+	} else {
+		u |= lexSignBit
+	}
+	return lexString(u)
+}
+func decLexFloat(s interface{}, f int, av types.AttributeValue) {
+	d := getF(s, f)
+	u := lexParse(av)
+	if u&lexSignBit != 0 {
+		u &^= lexSignBit
+	} else {
+		u = ^u
+	}
+	fv := math.Float64frombits(u)
+	if d.OverflowFloat(fv) {
+		panic(fmt.Errorf("value %g overflows %s", fv, d.Kind()))
+	}
+	d.SetFloat(fv)
+}
+
+func encLexTime(s interface{}, f int) types.AttributeValue {
+	t := readF(s, f).Interface().(time.Time).UTC()
+	if t.Year() < 0 || t.Year() > 9999 {
+		panic(fmt.Errorf("time %s is outside the years that sort correctly", t))
+	}
+	return &types.AttributeValueMemberS{Value: t.Format(lexTimeLayout)}
+}
+func decLexTime(s interface{}, f int, av types.AttributeValue) {
+	t, err := time.Parse(lexTimeLayout, av.(*types.AttributeValueMemberS).Value)
+	if err != nil {
+		panic(err)
+	}
+	getF(s, f).Set(reflect.ValueOf(t))
+}
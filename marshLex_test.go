@@ -0,0 +1,94 @@
+package ddbstruct
+
+import (
+	"math"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// lexCheck encodes each value in order, checks the encoded strings sort in
+// the same order, and checks each one decodes back to the original value.
+func lexCheck(t *testing.T, in, out interface{}, n int, set func(int), check func()) {
+	t.Helper()
+	f := typecalcField(t, in, 0)
+	var enc []string
+	for idx := 0; idx < n; idx++ {
+		set(idx)
+		av := f.enc(in, 0)
+		expectT(t, new(types.AttributeValueMemberS), av)
+		enc = append(enc, av.(*types.AttributeValueMemberS).Value)
+		f.dec(out, 0, av)
+		check()
+	}
+	if !sort.StringsAreSorted(enc) {
+		t.Fatalf("encoded values do not sort in order: %q", enc)
+	}
+}
+
+func TestLexIntOrder(t *testing.T) {
+	type z struct {
+		X int64 `ddb:"t=lex"`
+	}
+	vals := []int64{math.MinInt64, -1000, -9, -1, 0, 1, 9, 10, 1000, math.MaxInt64}
+	in, out := &z{}, &z{}
+	lexCheck(t, in, out, len(vals), func(idx int) { in.X = vals[idx] }, func() {
+		if in.X != out.X {
+			t.Fatalf("expected %d, got %d", in.X, out.X)
+		}
+	})
+}
+
+func TestLexUintOrder(t *testing.T) {
+	type z struct {
+		X uint16 `ddb:"t=lex"`
+	}
+	vals := []uint16{0, 9, 10, 100, math.MaxUint16}
+	in, out := &z{}, &z{}
+	lexCheck(t, in, out, len(vals), func(idx int) { in.X = vals[idx] }, func() {
+		if in.X != out.X {
+			t.Fatalf("expected %d, got %d", in.X, out.X)
+		}
+	})
+}
+
+func TestLexFloatOrder(t *testing.T) {
+	type z struct {
+		X float64 `ddb:"t=lex"`
+	}
+	vals := []float64{math.Inf(-1), -1e300, -10, -9.5, -1e-300, 0, 1e-300, 0.5, 9, 10, 1e300, math.Inf(1)}
+	in, out := &z{}, &z{}
+	lexCheck(t, in, out, len(vals), func(idx int) { in.X = vals[idx] }, func() {
+		if in.X != out.X {
+			t.Fatalf("expected %g, got %g", in.X, out.X)
+		}
+	})
+	in.X = math.NaN()
+	expectPanic(t, func() { typecalcField(t, in, 0).enc(in, 0) })
+}
+
+func TestLexTimeOrder(t *testing.T) {
+	type z struct {
+		X time.Time `ddb:"t=lex"`
+	}
+	base := time.Date(2021, 3, 4, 5, 6, 7, 0, time.FixedZone("east", 3600))
+	vals := []time.Time{base, base.Add(time.Nanosecond), base.Add(90 * time.Minute), base.AddDate(10, 0, 0)}
+	in, out := &z{}, &z{}
+	lexCheck(t, in, out, len(vals), func(idx int) { in.X = vals[idx] }, func() {
+		if !in.X.Equal(out.X) {
+			t.Fatalf("expected %v, got %v", in.X, out.X)
+		}
+	})
+}
+
+func TestLexDecodeOverflow(t *testing.T) {
+	type z struct {
+		X int8 `ddb:"t=lex"`
+	}
+	out := &z{}
+	f := typecalcField(t, out, 0)
+	expectPanic(t, func() { f.dec(out, 0, &types.AttributeValueMemberS{Value: "09223372036854775936"}) })
+	expectPanic(t, func() { f.dec(out, 0, &types.AttributeValueMemberS{Value: "12"}) })
+}
@@ -0,0 +1,35 @@
+package ddbstruct
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+func encList(ec *valueCodec) encodeFunc {
+	return func(s interface{}, f int) types.AttributeValue {
+		d := readF(s, f)
+		l := make([]types.AttributeValue, d.Len())
+		for idx := range l {
+			l[idx] = ec.encode(d.Index(idx))
+		}
+		return &types.AttributeValueMemberL{Value: l}
+	}
+}
+func decList(ec *valueCodec) decodeFunc {
+	return func(s interface{}, f int, av types.AttributeValue) {
+		d := getF(s, f)
+		l := av.(*types.AttributeValueMemberL).Value
+		if d.Kind() == reflect.Array {
+			if len(l) != d.Len() {
+				panic(fmt.Errorf("cannot decode list of %d elements into %s", len(l), d.Type()))
+			}
+		} else {
+			d.Set(reflect.MakeSlice(d.Type(), len(l), len(l)))
+		}
+		for idx := range l {
+			ec.decode(d.Index(idx), l[idx])
+		}
+	}
+}
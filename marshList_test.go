@@ -0,0 +1,110 @@
+package ddbstruct
+
+import (
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+func TestListStringRoundTrip(t *testing.T) {
+	type z struct{ X []string }
+	in := &z{X: []string{"one", "two", "three"}}
+	f := typecalcField(t, in, 0)
+	av := f.enc(in, 0)
+	expectT(t, new(types.AttributeValueMemberL), av)
+	if l := av.(*types.AttributeValueMemberL).Value; len(l) != 3 {
+		t.Fatalf("expected 3 elements, got %d", len(l))
+	}
+	out := &z{X: []string{"stale"}}
+	f.dec(out, 0, av)
+	compareSlice(t, in.X, out.X)
+}
+
+func TestListElementTypes(t *testing.T) {
+	type z struct{ X []float64 }
+	in := &z{X: []float64{1.5, -2}}
+	f := typecalcField(t, in, 0)
+	av := f.enc(in, 0).(*types.AttributeValueMemberL)
+	expectT(t, new(types.AttributeValueMemberN), av.Value[0])
+	out := &z{}
+	f.dec(out, 0, av)
+	compareSlice(t, in.X, out.X)
+}
+
+func TestListEmptyRoundTrip(t *testing.T) {
+	type z struct{ X []string }
+	in := &z{}
+	f := typecalcField(t, in, 0)
+	av := f.enc(in, 0)
+	expectT(t, new(types.AttributeValueMemberL), av)
+	out := &z{}
+	f.dec(out, 0, av)
+	if out.X == nil || len(out.X) != 0 {
+		t.Fatalf("expected empty slice, got %#v", out.X)
+	}
+}
+
+func TestListOfListsRoundTrip(t *testing.T) {
+	type z struct{ X [][]bool }
+	in := &z{X: [][]bool{{true}, {}, {false, true}}}
+	f := typecalcField(t, in, 0)
+	av := f.enc(in, 0)
+	out := &z{}
+	f.dec(out, 0, av)
+	if !reflect.DeepEqual(in.X, out.X) {
+		t.Fatalf("expected %v, got %v", in.X, out.X)
+	}
+}
+
+func TestListOfTextRoundTrip(t *testing.T) {
+	type z struct{ X []net.IP }
+	in := &z{X: []net.IP{net.IPv4(192, 0, 2, 1), net.IPv6loopback}}
+	f := typecalcField(t, in, 0)
+	av := f.enc(in, 0).(*types.AttributeValueMemberL)
+	expectT(t, new(types.AttributeValueMemberS), av.Value[0])
+	out := &z{}
+	f.dec(out, 0, av)
+	for idx := range in.X {
+		if !in.X[idx].Equal(out.X[idx]) {
+			t.Fatalf("expected %v, got %v", in.X[idx], out.X[idx])
+		}
+	}
+}
+
+func TestArrayRoundTrip(t *testing.T) {
+	type z struct{ X [3]string }
+	in := &z{X: [3]string{"a", "b", "c"}}
+	f := typecalcField(t, in, 0)
+	av := f.enc(in, 0)
+	expectT(t, new(types.AttributeValueMemberL), av)
+	out := &z{}
+	f.dec(out, 0, av)
+	if in.X != out.X {
+		t.Fatalf("expected %v, got %v", in.X, out.X)
+	}
+}
+
+func TestArrayWrongLength(t *testing.T) {
+	type z struct{ X [2]string }
+	av := &types.AttributeValueMemberL{Value: []types.AttributeValue{
+		&types.AttributeValueMemberS{Value: "a"},
+		&types.AttributeValueMemberS{Value: "b"},
+		&types.AttributeValueMemberS{Value: "c"},
+	}}
+	out := &z{}
+	f := typecalcField(t, out, 0)
+	expectPanic(t, func() { f.dec(out, 0, av) })
+}
+
+func TestListUnsupportedElement(t *testing.T) {
+	type z struct{ X []chan int }
+	f, err := parseFieldTag(reflect.TypeOf(z{}), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = f.typecalc(); err == nil {
+		t.Fatal("expected typecalc to fail")
+	}
+}
@@ -0,0 +1,117 @@
+package ddbstruct
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// mapKeyCodec converts map keys to and from the strings DynamoDB uses as
+// map attribute names.
+type mapKeyCodec struct {
+	t reflect.Type
+}
+
+func newMapKeyCodec(t reflect.Type) (*mapKeyCodec, error) {
+	switch t.Kind() {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &mapKeyCodec{t: t}, nil
+	}
+	if isTextEncoder(t) {
+		return &mapKeyCodec{t: t}, nil
+	}
+	return nil, fmt.Errorf("map key type %s cannot be used as an attribute name", t)
+}
+
+func (kc *mapKeyCodec) encode(k reflect.Value) string {
+	switch k.Kind() {
+	case reflect.String:
+		return k.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(k.Uint(), 10)
+	}
+	// map keys aren't addressable, so copy the key in case MarshalText has a pointer receiver
+	kp := reflect.New(kc.t)
+	kp.Elem().Set(k)
+	enc, ok := kp.Elem().Interface().(encoding.TextMarshaler)
+	if !ok {
+		enc = kp.Interface().(encoding.TextMarshaler)
+	}
+	buf, err := enc.MarshalText()
+	if err != nil {
+		panic(err)
+	}
+	return string(buf)
+}
+
+func (kc *mapKeyCodec) decode(s string) reflect.Value {
+	k := reflect.New(kc.t).Elem()
+	switch k.Kind() {
+	case reflect.String:
+		k.SetString(s)
+		return k
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			panic(fmt.Errorf("cannot convert map key %q to %s: %w", s, kc.t, err))
+		}
+		if k.OverflowInt(i) {
+			panic(fmt.Errorf("map key %q overflows %s", s, kc.t))
+		}
+		k.SetInt(i)
+		return k
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			panic(fmt.Errorf("cannot convert map key %q to %s: %w", s, kc.t, err))
+		}
+		if k.OverflowUint(i) {
+			panic(fmt.Errorf("map key %q overflows %s", s, kc.t))
+		}
+		k.SetUint(i)
+		return k
+	}
+	dec, ok := k.Interface().(encoding.TextUnmarshaler)
+	if !ok {
+		dec = k.Addr().Interface().(encoding.TextUnmarshaler)
+	}
+	if err := dec.UnmarshalText([]byte(s)); err != nil {
+		panic(err)
+	}
+	return k
+}
+
+func encMap(kc *mapKeyCodec, ec *valueCodec) encodeFunc {
+	return func(s interface{}, f int) types.AttributeValue {
+		d := readF(s, f)
+		m := make(map[string]types.AttributeValue, d.Len())
+		iter := d.MapRange()
+		for iter.Next() {
+			k := kc.encode(iter.Key())
+			if _, dup := m[k]; dup {
+				panic(fmt.Errorf("map key %q appears more than once", k))
+			}
+			m[k] = ec.encode(iter.Value())
+		}
+		return &types.AttributeValueMemberM{Value: m}
+	}
+}
+func decMap(kc *mapKeyCodec, ec *valueCodec) decodeFunc {
+	return func(s interface{}, f int, av types.AttributeValue) {
+		d := getF(s, f)
+		m := av.(*types.AttributeValueMemberM).Value
+		d.Set(reflect.MakeMapWithSize(d.Type(), len(m)))
+		v := reflect.New(d.Type().Elem()).Elem()
+		for k := range m {
+			ec.decode(v, m[k])
+			d.SetMapIndex(kc.decode(k), v)
+		}
+	}
+}
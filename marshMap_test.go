@@ -0,0 +1,112 @@
+package ddbstruct
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+func TestMapStringRoundTrip(t *testing.T) {
+	type z struct{ X map[string]string }
+	in := &z{X: map[string]string{"color": "red", "size": "large"}}
+	f := typecalcField(t, in, 0)
+	av := f.enc(in, 0)
+	expectT(t, new(types.AttributeValueMemberM), av)
+	expectT(t, new(types.AttributeValueMemberS), av.(*types.AttributeValueMemberM).Value["color"])
+	out := &z{X: map[string]string{"stale": "value"}}
+	f.dec(out, 0, av)
+	if !reflect.DeepEqual(in.X, out.X) {
+		t.Fatalf("expected %v, got %v", in.X, out.X)
+	}
+}
+
+func TestMapIntValuesRoundTrip(t *testing.T) {
+	type z struct{ X map[string]int64 }
+	in := &z{X: map[string]int64{"a": 1, "b": -20}}
+	f := typecalcField(t, in, 0)
+	av := f.enc(in, 0)
+	expectT(t, new(types.AttributeValueMemberN), av.(*types.AttributeValueMemberM).Value["b"])
+	out := &z{}
+	f.dec(out, 0, av)
+	if !reflect.DeepEqual(in.X, out.X) {
+		t.Fatalf("expected %v, got %v", in.X, out.X)
+	}
+}
+
+func TestMapIntKeysRoundTrip(t *testing.T) {
+	type z struct{ X map[int16][]string }
+	in := &z{X: map[int16][]string{-3: {"x"}, 400: {"y", "z"}}}
+	f := typecalcField(t, in, 0)
+	av := f.enc(in, 0).(*types.AttributeValueMemberM)
+	if _, ok := av.Value["-3"]; !ok {
+		t.Fatalf("expected key \"-3\", got %s", attrValString(av))
+	}
+	out := &z{}
+	f.dec(out, 0, av)
+	if !reflect.DeepEqual(in.X, out.X) {
+		t.Fatalf("expected %v, got %v", in.X, out.X)
+	}
+}
+
+func TestMapUintKeyOverflow(t *testing.T) {
+	type z struct{ X map[uint8]string }
+	av := &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+		"256": &types.AttributeValueMemberS{Value: "big"},
+	}}
+	out := &z{}
+	f := typecalcField(t, out, 0)
+	expectPanic(t, func() { f.dec(out, 0, av) })
+}
+
+type textKey struct{ v string }
+
+func (k textKey) MarshalText() ([]byte, error) { return []byte("key:" + k.v), nil }
+func (k *textKey) UnmarshalText(b []byte) error {
+	if !strings.HasPrefix(string(b), "key:") {
+		return fmt.Errorf("%q is not a key", b)
+	}
+	k.v = string(b[4:])
+	return nil
+}
+
+func TestMapTextKeysRoundTrip(t *testing.T) {
+	type z struct{ X map[textKey]bool }
+	in := &z{X: map[textKey]bool{{"abc"}: true}}
+	f := typecalcField(t, in, 0)
+	av := f.enc(in, 0).(*types.AttributeValueMemberM)
+	if _, ok := av.Value["key:abc"]; !ok {
+		t.Fatalf("expected key \"key:abc\", got %s", attrValString(av))
+	}
+	out := &z{}
+	f.dec(out, 0, av)
+	if len(out.X) != 1 || !out.X[textKey{"abc"}] {
+		t.Fatalf("expected %v, got %v", in.X, out.X)
+	}
+}
+
+func TestMapEmptyRoundTrip(t *testing.T) {
+	type z struct{ X map[string]string }
+	in := &z{}
+	f := typecalcField(t, in, 0)
+	av := f.enc(in, 0)
+	expectT(t, new(types.AttributeValueMemberM), av)
+	out := &z{}
+	f.dec(out, 0, av)
+	if out.X == nil || len(out.X) != 0 {
+		t.Fatalf("expected empty map, got %#v", out.X)
+	}
+}
+
+func TestMapUnsupportedKey(t *testing.T) {
+	type z struct{ X map[float64]string }
+	f, err := parseFieldTag(reflect.TypeOf(z{}), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = f.typecalc(); err == nil {
+		t.Fatal("expected typecalc to fail")
+	}
+}
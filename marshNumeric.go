@@ -2,17 +2,20 @@ package ddbstruct
 
 import (
 	"fmt"
+	"reflect"
 	"strconv"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 )
 
 func encInt(s interface{}, f int) types.AttributeValue {
-	return &types.AttributeValueMemberN{Value: strconv.FormatInt(getF(s, f).Int(), 10)}
+	return &types.AttributeValueMemberN{Value: strconv.FormatInt(readF(s, f).Int(), 10)}
 }
 func decInt(s interface{}, f int, av types.AttributeValue) {
-	d := getF(s, f)
-	sv := av.(*types.AttributeValueMemberN).Value
+	setIntString(getF(s, f), av.(*types.AttributeValueMemberN).Value)
+}
+func setIntString(d reflect.Value, sv string) {
 	i, err := strconv.ParseInt(sv, 10, 0)
 	if err != nil {
 		panic(fmt.Errorf("cannot convert %q to %s: %w", sv, d.Kind(), err))
@@ -24,11 +27,12 @@ func decInt(s interface{}, f int, av types.AttributeValue) {
 }
 
 func encUint(s interface{}, f int) types.AttributeValue {
-	return &types.AttributeValueMemberN{Value: strconv.FormatUint(getF(s, f).Uint(), 10)}
+	return &types.AttributeValueMemberN{Value: strconv.FormatUint(readF(s, f).Uint(), 10)}
 }
 func decUint(s interface{}, f int, av types.AttributeValue) {
-	d := getF(s, f)
-	sv := av.(*types.AttributeValueMemberN).Value
+	setUintString(getF(s, f), av.(*types.AttributeValueMemberN).Value)
+}
+func setUintString(d reflect.Value, sv string) {
 	i, err := strconv.ParseUint(sv, 10, 0)
 	if err != nil {
 		panic(fmt.Errorf("cannot convert %q to %s: %w", sv, d.Kind(), err))
@@ -39,10 +43,62 @@ func decUint(s interface{}, f int, av types.AttributeValue) {
 	d.SetUint(i)
 }
 
-func encFloat(bits int) encodeFunc {
+// encFixed writes an integer as a decimal number with scale digits after the
+// decimal point, so that 1234 with a scale of 2 is written as 12.34.
+func encFixed(scale int) encodeFunc {
 	return func(s interface{}, f int) types.AttributeValue {
+		d := readF(s, f)
+		var digits string
+		if d.CanInt() {
+			digits = strconv.FormatInt(d.Int(), 10)
+		} else {
+			digits = strconv.FormatUint(d.Uint(), 10)
+		}
+		var sign string
+		if digits[0] == '-' {
+			sign, digits = "-", digits[1:]
+		}
+		if scale == 0 {
+			return &types.AttributeValueMemberN{Value: sign + digits}
+		}
+		if pad := scale + 1 - len(digits); pad > 0 {
+			digits = strings.Repeat("0", pad) + digits
+		}
+		return &types.AttributeValueMemberN{Value: sign + digits[:len(digits)-scale] + "." + digits[len(digits)-scale:]}
+	}
+}
+func decFixed(scale int) decodeFunc {
+	return func(s interface{}, f int, av types.AttributeValue) {
 		d := getF(s, f)
-		return &types.AttributeValueMemberN{Value: strconv.FormatFloat(d.Float(), 'G', -1, bits)}
+		sv := av.(*types.AttributeValueMemberN).Value
+		neg, digits, exp, err := splitNumber(sv)
+		if err != nil {
+			panic(err)
+		}
+		// the integer is digits * 10^(exp+scale), which must not need rounding
+		shift := exp + scale
+		if shift < 0 {
+			panic(fmt.Errorf("value %q has more than %d digits after the decimal point", sv, scale))
+		}
+		if digits == "" {
+			digits, shift = "0", 0
+		}
+		digits += strings.Repeat("0", shift)
+		if neg {
+			digits = "-" + digits
+		}
+		if d.CanInt() {
+			setIntString(d, digits)
+		} else {
+			setUintString(d, digits)
+		}
+	}
+}
+
+func encFloat(bits int) encodeFunc {
+	return func(s interface{}, f int) types.AttributeValue {
+		d := readF(s, f)
+		return newNumber(strconv.FormatFloat(d.Float(), 'G', -1, bits))
 	}
 }
 func decFloat(bits int) decodeFunc {
@@ -1,6 +1,8 @@
 package ddbstruct
 
 import (
+	"math"
+	"reflect"
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
@@ -295,3 +297,140 @@ func TestFloat64PtrReuseRoundTrip(t *testing.T) {
 		t.Fatalf("expected %g, got %g", *in.X, *out.X)
 	}
 }
+
+type testPriority int8
+type testFlags uint16
+
+func TestIntegerKindsItemRoundTrip(t *testing.T) {
+	type z struct {
+		I   int
+		I8  int8
+		I16 int16
+		I32 int32
+		I64 int64
+		U   uint
+		U8  uint8
+		U16 uint16
+		U32 uint32
+		U64 uint64
+		P   testPriority
+		F   testFlags
+		PI  *int32
+		PP  *testPriority
+	}
+	pi, pp := int32(-7), testPriority(3)
+	in := &z{
+		I: -1, I8: math.MinInt8, I16: math.MinInt16, I32: math.MinInt32, I64: math.MinInt64,
+		U: 1, U8: math.MaxUint8, U16: math.MaxUint16, U32: math.MaxUint32, U64: math.MaxUint64,
+		P: -2, F: 0x8001, PI: &pi, PP: &pp,
+	}
+	md := cache.get(in)
+	m, err := md.encode(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k := range m {
+		expectT(t, new(types.AttributeValueMemberN), m[k])
+	}
+	if n := m["U64"].(*types.AttributeValueMemberN).Value; n != "18446744073709551615" {
+		t.Fatalf("expected max uint64, got %s", n)
+	}
+	out := &z{}
+	if err = md.decode(out, m, false); err != nil {
+		t.Fatal(err)
+	}
+	if out.PI == nil || out.PP == nil || *out.PI != pi || *out.PP != pp {
+		t.Fatalf("pointer fields differ: expected %v %v, got %v %v", pi, pp, out.PI, out.PP)
+	}
+	out.PI, out.PP, in.PI, in.PP = nil, nil, nil, nil
+	if *in != *out {
+		t.Fatalf("expected %+v, got %+v", *in, *out)
+	}
+}
+
+func TestIntegerKindsItemOverflow(t *testing.T) {
+	type z struct {
+		P testPriority
+		U uint32
+	}
+	md := cache.get(&z{})
+	for _, m := range []avmap{
+		{"P": &types.AttributeValueMemberN{Value: "128"}, "U": &types.AttributeValueMemberN{Value: "0"}},
+		{"P": &types.AttributeValueMemberN{Value: "-129"}, "U": &types.AttributeValueMemberN{Value: "0"}},
+		{"P": &types.AttributeValueMemberN{Value: "0"}, "U": &types.AttributeValueMemberN{Value: "4294967296"}},
+		{"P": &types.AttributeValueMemberN{Value: "0"}, "U": &types.AttributeValueMemberN{Value: "-1"}},
+	} {
+		expectPanic(t, func() { md.decode(&z{}, m, false) })
+	}
+}
+
+func TestFixedRoundTrip(t *testing.T) {
+	type z struct {
+		X int64 `ddb:"t=fixed:2"`
+	}
+	for v, n := range map[int64]string{1234: "12.34", -5: "-0.05", 0: "0.00", 100: "1.00", math.MinInt64: "-92233720368547758.08"} {
+		in := &z{X: v}
+		f := typecalcField(t, in, 0)
+		av := f.enc(in, 0)
+		expectT(t, new(types.AttributeValueMemberN), av)
+		if av.(*types.AttributeValueMemberN).Value != n {
+			t.Fatalf("expected %s, got %s", n, av.(*types.AttributeValueMemberN).Value)
+		}
+		out := &z{}
+		f.dec(out, 0, av)
+		if in.X != out.X {
+			t.Fatalf("expected %d, got %d", in.X, out.X)
+		}
+	}
+}
+
+func TestFixedDecodeNormalized(t *testing.T) {
+	type z struct {
+		X uint32 `ddb:"t=fixed:3"`
+	}
+	out := &z{}
+	f := typecalcField(t, out, 0)
+	// DynamoDB drops trailing zeros, and may return any equivalent form
+	for n, v := range map[string]uint32{"12.3": 12300, "12.340": 12340, "7": 7000, "1.5E+2": 150000, "0": 0} {
+		f.dec(out, 0, &types.AttributeValueMemberN{Value: n})
+		if out.X != v {
+			t.Fatalf("%s: expected %d, got %d", n, v, out.X)
+		}
+	}
+}
+
+func TestFixedDecodeTooPrecise(t *testing.T) {
+	type z struct {
+		X int64 `ddb:"t=fixed:2"`
+	}
+	out := &z{}
+	f := typecalcField(t, out, 0)
+	expectPanic(t, func() { f.dec(out, 0, &types.AttributeValueMemberN{Value: "12.345"}) })
+}
+
+func TestFixedDecodeOverflow(t *testing.T) {
+	type z struct {
+		X int8  `ddb:"t=fixed:1"`
+		Y uint8 `ddb:"t=fixed:1"`
+	}
+	out := &z{}
+	expectPanic(t, func() { typecalcField(t, out, 0).dec(out, 0, &types.AttributeValueMemberN{Value: "12.8"}) })
+	expectPanic(t, func() { typecalcField(t, out, 1).dec(out, 1, &types.AttributeValueMemberN{Value: "-1"}) })
+}
+
+func TestFixedInvalid(t *testing.T) {
+	type z struct {
+		A float64 `ddb:"t=fixed:2"`
+		B int64   `ddb:"t=fixed:x"`
+		C int64   `ddb:"t=fixed:-1"`
+	}
+	for idx := 0; idx < 3; idx++ {
+		f, err := parseFieldTag(reflect.TypeOf(z{}), idx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = f.typecalc(); err == nil {
+			t.Errorf("expected typecalc of field %d to fail", idx)
+		}
+	}
+}
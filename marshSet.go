@@ -0,0 +1,219 @@
+package ddbstruct
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// DynamoDB refuses to store a set with no members, so an empty set can only
+// be written by leaving the attribute out.
+var errEmptySet = errors.New("cannot encode an empty set; DynamoDB does not allow empty sets (tag the field opt to omit it)")
+
+// setCodec encodes a slice, or a map used as a set (map[T]struct{}), as one
+// of the DynamoDB set types. Members are encoded with the same logic that
+// typecalc picks for a field of the member type, and the set type follows
+// from what the members encode as, so that a member type with a registered
+// codec (an enum stored as a string, say) makes the set type it should.
+type setCodec struct {
+	name string
+	ec   *valueCodec
+	keys bool // members are the keys of a map, not the elements of a slice
+}
+
+func newSetCodec(name string, t reflect.Type) (*setCodec, error) {
+	sc := &setCodec{name: name}
+	var mt reflect.Type
+	switch {
+	case t.Kind() == reflect.Slice:
+		mt = t.Elem()
+	case isMapSet(t):
+		mt, sc.keys = t.Key(), true
+	default:
+		return nil, fmt.Errorf("field %q of type %s cannot be encoded as a set", name, t)
+	}
+	if !isSetMember(mt) {
+		return nil, fmt.Errorf("field %q of type %s cannot be encoded as a set of %s", name, t, mt)
+	}
+	var err error
+	sc.ec, err = newValueCodec(name+"[]", mt)
+	if err != nil {
+		return nil, err
+	}
+	return sc, nil
+}
+
+// isSetMember reports whether t is a type whose values can be encoded as a
+// string, number or binary attribute, as set members must be.
+func isSetMember(t reflect.Type) bool {
+	if c, _ := codecs.lookup(t); c != nil {
+		return true
+	}
+	switch {
+	case isAttributeEncoder(t), isBigNumber(t):
+		return true
+	case t.Kind() == reflect.String, isNumberKind(t.Kind()):
+		return true
+	case t == typeBytes, t.Kind() == reflect.Array && isByteSeq(t):
+		return true
+	case isTextEncoder(t), isBinEncoder(t):
+		return true
+	}
+	return false
+}
+
+// isMapSet reports whether t is a map with empty struct values, the usual
+// way of spelling a set in Go.
+func isMapSet(t reflect.Type) bool {
+	return t.Kind() == reflect.Map && t.Elem().Kind() == reflect.Struct && t.Elem().NumField() == 0
+}
+
+func isNumberKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func (sc *setCodec) members(d reflect.Value) []reflect.Value {
+	if sc.keys {
+		return d.MapKeys()
+	}
+	m := make([]reflect.Value, d.Len())
+	for idx := range m {
+		m[idx] = d.Index(idx)
+	}
+	return m
+}
+
+func encSet(sc *setCodec) encodeFunc {
+	return func(s interface{}, f int) types.AttributeValue {
+		m := sc.members(readF(s, f))
+		if len(m) == 0 {
+			panic(errEmptySet)
+		}
+		var ss, ns []string
+		var bs [][]byte
+		for idx := range m {
+			switch av := sc.ec.encode(m[idx]).(type) {
+			case *types.AttributeValueMemberS:
+				ss = append(ss, av.Value)
+			case *types.AttributeValueMemberN:
+				ns = append(ns, av.Value)
+			case *types.AttributeValueMemberB:
+				bs = append(bs, av.Value)
+			default:
+				panic(fmt.Errorf("set member %v of %s encoded as %s, not a string, number or binary", m[idx], sc.name, attrValString(av)))
+			}
+		}
+		switch len(m) {
+		case len(ss):
+			return newStringSet(ss)
+		case len(ns):
+			return newNumberSet(ns)
+		case len(bs):
+			return newBinarySet(bs)
+		}
+		panic(fmt.Errorf("members of %s encoded as more than one type, but a set can only hold one", sc.name))
+	}
+}
+func decSet(sc *setCodec) decodeFunc {
+	return func(s interface{}, f int, av types.AttributeValue) {
+		var m []types.AttributeValue
+		switch sv := av.(type) {
+		case *types.AttributeValueMemberSS:
+			for _, v := range sv.Value {
+				m = append(m, &types.AttributeValueMemberS{Value: v})
+			}
+		case *types.AttributeValueMemberNS:
+			for _, v := range sv.Value {
+				m = append(m, &types.AttributeValueMemberN{Value: v})
+			}
+		case *types.AttributeValueMemberBS:
+			for _, v := range sv.Value {
+				m = append(m, &types.AttributeValueMemberB{Value: v})
+			}
+		default:
+			panic(fmt.Errorf("cannot decode %s into %s, which is a set", attrValString(av), sc.name))
+		}
+		d := getF(s, f)
+		if sc.keys {
+			d.Set(reflect.MakeMapWithSize(d.Type(), len(m)))
+			k := reflect.New(d.Type().Key()).Elem()
+			present := reflect.New(d.Type().Elem()).Elem()
+			for idx := range m {
+				sc.ec.decode(k, m[idx])
+				d.SetMapIndex(k, present)
+			}
+			return
+		}
+		d.Set(reflect.MakeSlice(d.Type(), len(m), len(m)))
+		for idx := range m {
+			sc.ec.decode(d.Index(idx), m[idx])
+		}
+	}
+}
+
+// newStringSet sorts ss and removes duplicates from it.
+func newStringSet(ss []string) *types.AttributeValueMemberSS {
+	sort.Strings(ss)
+	out := ss[:0]
+	for idx := range ss {
+		if idx == 0 || ss[idx] != ss[idx-1] {
+			out = append(out, ss[idx])
+		}
+	}
+	return &types.AttributeValueMemberSS{Value: out}
+}
+
+// newNumberSet sorts ns by numeric value and removes duplicates from it.
+func newNumberSet(ns []string) *types.AttributeValueMemberNS {
+	nv := make([]*big.Rat, len(ns))
+	for idx := range ns {
+		var ok bool
+		nv[idx], ok = new(big.Rat).SetString(ns[idx])
+		if !ok {
+			panic(fmt.Errorf("set member %q is not a number", ns[idx]))
+		}
+	}
+	sort.Sort(numberSet{ns, nv})
+	out := ns[:0]
+	for idx := range ns {
+		if idx == 0 || nv[idx].Cmp(nv[idx-1]) != 0 {
+			out = append(out, ns[idx])
+		}
+	}
+	return &types.AttributeValueMemberNS{Value: out}
+}
+
+type numberSet struct {
+	s []string
+	v []*big.Rat
+}
+
+func (ns numberSet) Len() int           { return len(ns.s) }
+func (ns numberSet) Less(i, j int) bool { return ns.v[i].Cmp(ns.v[j]) < 0 }
+func (ns numberSet) Swap(i, j int) {
+	ns.s[i], ns.s[j] = ns.s[j], ns.s[i]
+	ns.v[i], ns.v[j] = ns.v[j], ns.v[i]
+}
+
+// newBinarySet sorts bs and removes duplicates from it.
+func newBinarySet(bs [][]byte) *types.AttributeValueMemberBS {
+	sort.Slice(bs, func(i, j int) bool { return bytes.Compare(bs[i], bs[j]) < 0 })
+	out := bs[:0]
+	for idx := range bs {
+		if idx == 0 || !bytes.Equal(bs[idx], bs[idx-1]) {
+			out = append(out, bs[idx])
+		}
+	}
+	return &types.AttributeValueMemberBS{Value: out}
+}
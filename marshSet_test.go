@@ -0,0 +1,139 @@
+package ddbstruct
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+func TestStringSetRoundTrip(t *testing.T) {
+	type z struct {
+		X []string `ddb:"t=set"`
+	}
+	in := &z{X: []string{"pear", "apple", "pear", "fig"}}
+	f := typecalcField(t, in, 0)
+	av := f.enc(in, 0)
+	expectT(t, new(types.AttributeValueMemberSS), av)
+	compareSlice(t, []string{"apple", "fig", "pear"}, av.(*types.AttributeValueMemberSS).Value)
+	if len(in.X) != 4 {
+		t.Fatalf("encoding modified the source slice: %v", in.X)
+	}
+	out := &z{}
+	f.dec(out, 0, av)
+	compareSlice(t, []string{"apple", "fig", "pear"}, out.X)
+}
+
+func TestNumberSetRoundTrip(t *testing.T) {
+	type z struct {
+		X []float64 `ddb:"t=set"`
+	}
+	in := &z{X: []float64{10, 9, -1.5, 10}}
+	f := typecalcField(t, in, 0)
+	av := f.enc(in, 0)
+	expectT(t, new(types.AttributeValueMemberNS), av)
+	compareSlice(t, []string{"-1.5", "9", "10"}, av.(*types.AttributeValueMemberNS).Value)
+	out := &z{}
+	f.dec(out, 0, av)
+	compareSlice(t, []float64{-1.5, 9, 10}, out.X)
+}
+
+func TestBinarySetRoundTrip(t *testing.T) {
+	type z struct {
+		X [][]byte `ddb:"t=set"`
+	}
+	in := &z{X: [][]byte{{2}, {1, 2}, {2}}}
+	f := typecalcField(t, in, 0)
+	av := f.enc(in, 0)
+	expectT(t, new(types.AttributeValueMemberBS), av)
+	out := &z{}
+	f.dec(out, 0, av)
+	if !reflect.DeepEqual([][]byte{{1, 2}, {2}}, out.X) {
+		t.Fatalf("expected [[1 2] [2]], got %v", out.X)
+	}
+}
+
+func TestMapSetRoundTrip(t *testing.T) {
+	type z struct{ X map[string]struct{} }
+	in := &z{X: map[string]struct{}{"b": {}, "a": {}}}
+	f := typecalcField(t, in, 0)
+	av := f.enc(in, 0)
+	expectT(t, new(types.AttributeValueMemberSS), av)
+	compareSlice(t, []string{"a", "b"}, av.(*types.AttributeValueMemberSS).Value)
+	out := &z{}
+	f.dec(out, 0, av)
+	if !reflect.DeepEqual(in.X, out.X) {
+		t.Fatalf("expected %v, got %v", in.X, out.X)
+	}
+}
+
+func TestEmptySet(t *testing.T) {
+	type z struct {
+		X []string `ddb:"t=set"`
+	}
+	in := &z{X: []string{}}
+	f := typecalcField(t, in, 0)
+	expectPanic(t, func() { f.enc(in, 0) })
+	expectPanic(t, func() { f.appendAV(avmap{}, in) })
+}
+
+func TestEmptySetOptional(t *testing.T) {
+	type z struct {
+		X []string `ddb:"t=set,opt"`
+	}
+	in := &z{X: []string{}}
+	f := typecalcField(t, in, 0)
+	m := avmap{}
+	if err := f.appendAV(m, in); err != nil {
+		t.Fatal(err)
+	}
+	if len(m) != 0 {
+		t.Fatalf("expected no attributes, got %v", m)
+	}
+}
+
+func TestSetUnsupported(t *testing.T) {
+	type z struct {
+		X []bool `ddb:"t=set"`
+	}
+	f, err := parseFieldTag(reflect.TypeOf(z{}), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = f.typecalc(); err == nil {
+		t.Fatal("expected typecalc to fail")
+	}
+}
+
+func TestEnumSetRoundTrip(t *testing.T) {
+	type z struct {
+		X []enumStatus `ddb:"t=set"`
+		Y map[enumStatus]struct{}
+	}
+	registerEnumStatus()
+	in := &z{X: []enumStatus{enumClosed, enumActive}, Y: map[enumStatus]struct{}{enumActive: {}}}
+	m, err := cache.get(in).encode(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectT(t, new(types.AttributeValueMemberSS), m["X"])
+	compareSlice(t, []string{"ACTIVE", "CLOSED"}, m["X"].(*types.AttributeValueMemberSS).Value)
+	expectT(t, new(types.AttributeValueMemberSS), m["Y"])
+	out := &z{}
+	if err = cache.get(out).decode(out, m, false); err != nil {
+		t.Fatal(err)
+	}
+	compareSlice(t, []enumStatus{enumActive, enumClosed}, out.X)
+	if !reflect.DeepEqual(in.Y, out.Y) {
+		t.Fatalf("expected %v, got %v", in.Y, out.Y)
+	}
+}
+
+func TestSetWrongAttribute(t *testing.T) {
+	type z struct {
+		X []string `ddb:"t=set"`
+	}
+	out := &z{}
+	f := typecalcField(t, out, 0)
+	expectPanic(t, func() { f.dec(out, 0, &types.AttributeValueMemberS{Value: "a"}) })
+}
@@ -0,0 +1,43 @@
+package ddbstruct
+
+import (
+	"reflect"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// nested structs are looked up in the metadata cache when they're first
+// encoded or decoded, rather than during typecalc, because typecalc runs with
+// the cache locked (and because a struct may refer to itself).
+
+// encStruct encodes a nil pointer as an empty map, which decStruct decodes
+// back to nil, rather than encoding a new zero struct, which would recurse
+// forever for a struct that points to its own type. (A field tagged null is
+// written as NULL before it gets here.) A pointer to a struct that encodes as
+// an empty map, because all of its fields are optional and zero, is decoded
+// as nil too.
+func encStruct(s interface{}, f int) types.AttributeValue {
+	if isNil(reflect.ValueOf(s).Elem().Field(f)) {
+		return &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{}}
+	}
+	d := readF(s, f).Addr().Interface()
+	m, err := cache.get(d).encode(d)
+	if err != nil {
+		panic(err)
+	}
+	return &types.AttributeValueMemberM{Value: m}
+}
+func decStruct(s interface{}, f int, av types.AttributeValue) {
+	m := av.(*types.AttributeValueMemberM).Value
+	if v := reflect.ValueOf(s).Elem().Field(f); v.Kind() == reflect.Pointer && len(m) == 0 {
+		v.Set(reflect.Zero(v.Type()))
+		return
+	}
+	fp := getF(s, f)
+	fp.Set(reflect.Zero(fp.Type())) // decoding replaces the whole struct
+	d := fp.Addr().Interface()
+	err := cache.get(d).decode(d, m, false)
+	if err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,135 @@
+package ddbstruct
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+type testAddress struct {
+	Street string `ddb:"n=street"`
+	City   string `ddb:"n=city"`
+	Unit   string `ddb:"opt"`
+}
+
+func TestStructRoundTrip(t *testing.T) {
+	type z struct{ X testAddress }
+	in := &z{X: testAddress{Street: "1 Main St", City: "Springfield"}}
+	f := typecalcField(t, in, 0)
+	av := f.enc(in, 0)
+	expectT(t, new(types.AttributeValueMemberM), av)
+	m := av.(*types.AttributeValueMemberM).Value
+	if len(m) != 2 {
+		t.Fatalf("expected 2 attributes, got %s", attrValString(av))
+	}
+	expectT(t, new(types.AttributeValueMemberS), m["street"])
+	out := &z{X: testAddress{Unit: "stale"}}
+	f.dec(out, 0, av)
+	if in.X != out.X {
+		t.Fatalf("expected %+v, got %+v", in.X, out.X)
+	}
+}
+
+func TestStructPtrRoundTrip(t *testing.T) {
+	type z struct{ X *testAddress }
+	in := &z{X: &testAddress{Street: "2 Elm St", City: "Shelbyville", Unit: "4B"}}
+	f := typecalcField(t, in, 0)
+	av := f.enc(in, 0)
+	expectT(t, new(types.AttributeValueMemberM), av)
+	out := &z{}
+	f.dec(out, 0, av)
+	if out.X == nil {
+		t.Fatal("out is nil")
+	}
+	if *in.X != *out.X {
+		t.Fatalf("expected %+v, got %+v", *in.X, *out.X)
+	}
+}
+
+func TestStructSliceRoundTrip(t *testing.T) {
+	type z struct{ X []testAddress }
+	in := &z{X: []testAddress{{Street: "a", City: "b"}, {Street: "c", City: "d", Unit: "e"}}}
+	f := typecalcField(t, in, 0)
+	av := f.enc(in, 0).(*types.AttributeValueMemberL)
+	expectT(t, new(types.AttributeValueMemberM), av.Value[1])
+	out := &z{}
+	f.dec(out, 0, av)
+	if !reflect.DeepEqual(in.X, out.X) {
+		t.Fatalf("expected %+v, got %+v", in.X, out.X)
+	}
+}
+
+func TestStructRecursiveRoundTrip(t *testing.T) {
+	type node struct {
+		Name     string
+		Children []node
+		Next     *node
+	}
+	type z struct{ X node }
+	in := &z{X: node{Name: "root", Children: []node{{Name: "leaf", Children: []node{}}},
+		Next: &node{Name: "sibling", Children: []node{}}}}
+	f := typecalcField(t, in, 0)
+	av := f.enc(in, 0)
+	out := &z{}
+	f.dec(out, 0, av)
+	if !reflect.DeepEqual(in.X, out.X) {
+		t.Fatalf("expected %+v, got %+v", in.X, out.X)
+	}
+}
+
+func TestStructMissingAttribute(t *testing.T) {
+	type z struct{ X testAddress }
+	av := &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+		"street": &types.AttributeValueMemberS{Value: "1 Main St"},
+	}}
+	out := &z{}
+	f := typecalcField(t, out, 0)
+	expectPanic(t, func() { f.dec(out, 0, av) })
+}
+
+func TestStructNilSelfPointer(t *testing.T) {
+	type node struct {
+		ID   string `ddb:"pk"`
+		Next *node
+	}
+	in := &node{ID: "a"}
+	m, err := cache.get(in).encode(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectT(t, new(types.AttributeValueMemberM), m["Next"])
+	if n := len(m["Next"].(*types.AttributeValueMemberM).Value); n != 0 {
+		t.Fatalf("expected an empty map for a nil pointer, got %s", attrValString(m["Next"]))
+	}
+	if in.Next != nil {
+		t.Fatal("encoding allocated the nil pointer")
+	}
+	out := &node{Next: &node{ID: "stale"}}
+	if err = cache.get(out).decode(out, m, false); err != nil {
+		t.Fatal(err)
+	}
+	if out.ID != "a" || out.Next != nil {
+		t.Fatalf("expected %+v, got %+v", in, out)
+	}
+}
+
+func TestStructNilPointerNull(t *testing.T) {
+	type z struct {
+		X *testAddress `ddb:"null"`
+		L []*testAddress
+	}
+	in := &z{L: []*testAddress{nil, {Street: "a", City: "b"}}}
+	m, err := cache.get(in).encode(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectT(t, new(types.AttributeValueMemberNULL), m["X"])
+	out := &z{X: &testAddress{Street: "stale"}}
+	if err = cache.get(out).decode(out, m, false); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Fatalf("expected %+v, got %+v", in, out)
+	}
+}
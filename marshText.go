@@ -11,7 +11,7 @@ var intfTextMarshaler = reflect.TypeOf(new(encoding.TextMarshaler)).Elem()
 var intfTextUnmarshaler = reflect.TypeOf(new(encoding.TextUnmarshaler)).Elem()
 
 func encText(s interface{}, f int) types.AttributeValue {
-	fp := getF(s, f)
+	fp := readF(s, f)
 	enc, ok := fp.Interface().(encoding.TextMarshaler)
 	if !ok {
 		enc, ok = fp.Addr().Interface().(encoding.TextMarshaler)
@@ -2,6 +2,8 @@ package ddbstruct
 
 import (
 	"net"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -69,3 +71,60 @@ func TestIPPtrTextRoundTrip(t *testing.T) {
 		t.Fatalf("expected %s, got %s", *in.X, *out.X)
 	}
 }
+
+// textLevel64 is an int64 that marshals itself as text, like a log level.
+type textLevel64 int64
+
+func (l textLevel64) MarshalText() ([]byte, error) {
+	return []byte("L" + strconv.FormatInt(int64(l), 10)), nil
+}
+func (l *textLevel64) UnmarshalText(b []byte) error {
+	n, err := strconv.ParseInt(strings.TrimPrefix(string(b), "L"), 10, 64)
+	*l = textLevel64(n)
+	return err
+}
+
+func TestTextPtrKeepsTextEncoding(t *testing.T) {
+	type z struct{ X *textLevel64 }
+	l := textLevel64(3)
+	in := &z{X: &l}
+	f := typecalcField(t, in, 0)
+	av := f.enc(in, 0)
+	expectT(t, new(types.AttributeValueMemberS), av)
+	if s := av.(*types.AttributeValueMemberS).Value; s != "L3" {
+		t.Fatalf("expected \"L3\", got %q", s)
+	}
+	out := &z{}
+	f.dec(out, 0, av)
+	if out.X == nil || *out.X != l {
+		t.Fatalf("expected %v, got %v", l, out.X)
+	}
+}
+
+// textLevel is an int that marshals itself as text.
+type textLevel int
+
+func (l textLevel) MarshalText() ([]byte, error) {
+	return []byte("L" + strconv.Itoa(int(l))), nil
+}
+func (l *textLevel) UnmarshalText(b []byte) error {
+	n, err := strconv.Atoi(strings.TrimPrefix(string(b), "L"))
+	*l = textLevel(n)
+	return err
+}
+
+func TestTextIntKeepsTextEncoding(t *testing.T) {
+	type z struct{ X textLevel }
+	in := &z{X: 3}
+	f := typecalcField(t, in, 0)
+	av := f.enc(in, 0)
+	expectT(t, new(types.AttributeValueMemberS), av)
+	if s := av.(*types.AttributeValueMemberS).Value; s != "L3" {
+		t.Fatalf("expected \"L3\", got %q", s)
+	}
+	out := &z{}
+	f.dec(out, 0, av)
+	if out.X != in.X {
+		t.Fatalf("expected %v, got %v", in.X, out.X)
+	}
+}
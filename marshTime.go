@@ -1,15 +1,18 @@
 package ddbstruct
 
 import (
+	"fmt"
+	"math"
 	"reflect"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 )
 
 func encDurationString(s interface{}, f int) types.AttributeValue {
-	return &types.AttributeValueMemberS{Value: getF(s, f).Interface().(time.Duration).String()}
+	return &types.AttributeValueMemberS{Value: readF(s, f).Interface().(time.Duration).String()}
 }
 func decDurationString(s interface{}, f int, av types.AttributeValue) {
 	t, err := time.ParseDuration(av.(*types.AttributeValueMemberS).Value)
@@ -19,7 +22,7 @@ func decDurationString(s interface{}, f int, av types.AttributeValue) {
 	getF(s, f).Set(reflect.ValueOf(t))
 }
 func encDurationNano(s interface{}, f int) types.AttributeValue {
-	ns := getF(s, f).Interface().(time.Duration).Nanoseconds()
+	ns := readF(s, f).Interface().(time.Duration).Nanoseconds()
 	return &types.AttributeValueMemberN{Value: strconv.FormatInt(ns, 10)}
 }
 func decDurationNano(s interface{}, f int, av types.AttributeValue) {
@@ -30,7 +33,7 @@ func decDurationNano(s interface{}, f int, av types.AttributeValue) {
 	getF(s, f).SetInt(int64(time.Nanosecond) * nv)
 }
 func encDurationSec(s interface{}, f int) types.AttributeValue {
-	sv := getF(s, f).Interface().(time.Duration).Seconds()
+	sv := readF(s, f).Interface().(time.Duration).Seconds()
 	return &types.AttributeValueMemberN{Value: strconv.FormatFloat(sv, 'f', -1, 64)}
 }
 func decDurationSec(s interface{}, f int, av types.AttributeValue) {
@@ -42,7 +45,7 @@ func decDurationSec(s interface{}, f int, av types.AttributeValue) {
 }
 
 func encTimeNano(s interface{}, f int) types.AttributeValue {
-	t := getF(s, f).Interface().(time.Time)
+	t := readF(s, f).Interface().(time.Time)
 	return &types.AttributeValueMemberN{Value: strconv.FormatInt(t.UnixNano(), 10)}
 }
 func decTimeNano(s interface{}, f int, av types.AttributeValue) {
@@ -53,7 +56,7 @@ func decTimeNano(s interface{}, f int, av types.AttributeValue) {
 	getF(s, f).Set(reflect.ValueOf(time.Unix(0, nv)))
 }
 func encTimeEpoch(s interface{}, f int) types.AttributeValue {
-	t := getF(s, f).Interface().(time.Time).Unix()
+	t := readF(s, f).Interface().(time.Time).Unix()
 	return &types.AttributeValueMemberN{Value: strconv.FormatInt(t, 10)}
 }
 func decTimeEpoch(s interface{}, f int, av types.AttributeValue) {
@@ -63,3 +66,207 @@ func decTimeEpoch(s interface{}, f int, av types.AttributeValue) {
 	}
 	getF(s, f).Set(reflect.ValueOf(time.Unix(nv, 0)))
 }
+
+func encDurationMilli(s interface{}, f int) types.AttributeValue {
+	ms := readF(s, f).Interface().(time.Duration).Milliseconds()
+	return &types.AttributeValueMemberN{Value: strconv.FormatInt(ms, 10)}
+}
+func decDurationMilli(s interface{}, f int, av types.AttributeValue) {
+	nv, err := strconv.ParseInt(av.(*types.AttributeValueMemberN).Value, 10, 64)
+	if err != nil {
+		panic(err)
+	}
+	getF(s, f).SetInt(int64(time.Millisecond) * nv)
+}
+func encDurationMicro(s interface{}, f int) types.AttributeValue {
+	us := readF(s, f).Interface().(time.Duration).Microseconds()
+	return &types.AttributeValueMemberN{Value: strconv.FormatInt(us, 10)}
+}
+func decDurationMicro(s interface{}, f int, av types.AttributeValue) {
+	nv, err := strconv.ParseInt(av.(*types.AttributeValueMemberN).Value, 10, 64)
+	if err != nil {
+		panic(err)
+	}
+	getF(s, f).SetInt(int64(time.Microsecond) * nv)
+}
+func encDurationISO(s interface{}, f int) types.AttributeValue {
+	return &types.AttributeValueMemberS{Value: formatISODuration(readF(s, f).Interface().(time.Duration))}
+}
+func decDurationISO(s interface{}, f int, av types.AttributeValue) {
+	d, err := parseISODuration(av.(*types.AttributeValueMemberS).Value)
+	if err != nil {
+		panic(err)
+	}
+	getF(s, f).SetInt(int64(d))
+}
+
+func encTimeMilli(s interface{}, f int) types.AttributeValue {
+	t := readF(s, f).Interface().(time.Time).UnixMilli()
+	return &types.AttributeValueMemberN{Value: strconv.FormatInt(t, 10)}
+}
+func decTimeMilli(s interface{}, f int, av types.AttributeValue) {
+	nv, err := strconv.ParseInt(av.(*types.AttributeValueMemberN).Value, 10, 64)
+	if err != nil {
+		panic(err)
+	}
+	getF(s, f).Set(reflect.ValueOf(time.UnixMilli(nv)))
+}
+func encTimeMicro(s interface{}, f int) types.AttributeValue {
+	t := readF(s, f).Interface().(time.Time).UnixMicro()
+	return &types.AttributeValueMemberN{Value: strconv.FormatInt(t, 10)}
+}
+func decTimeMicro(s interface{}, f int, av types.AttributeValue) {
+	nv, err := strconv.ParseInt(av.(*types.AttributeValueMemberN).Value, 10, 64)
+	if err != nil {
+		panic(err)
+	}
+	getF(s, f).Set(reflect.ValueOf(time.UnixMicro(nv)))
+}
+
+// encTimeLayout writes a time as a string formatted with layout, converting
+// it to UTC first if utc is set.
+func encTimeLayout(layout string, utc bool) encodeFunc {
+	return func(s interface{}, f int) types.AttributeValue {
+		t := readF(s, f).Interface().(time.Time)
+		if utc {
+			t = t.UTC()
+		}
+		return &types.AttributeValueMemberS{Value: t.Format(layout)}
+	}
+}
+func decTimeLayout(layout string) decodeFunc {
+	return func(s interface{}, f int, av types.AttributeValue) {
+		t, err := time.Parse(layout, av.(*types.AttributeValueMemberS).Value)
+		if err != nil {
+			panic(err)
+		}
+		getF(s, f).Set(reflect.ValueOf(t))
+	}
+}
+
+// formatISODuration writes d as an ISO-8601 duration, such as PT1H30M. Only
+// hours, minutes and seconds are used, since days and larger units don't
+// have a fixed length.
+func formatISODuration(d time.Duration) string {
+	if d == 0 {
+		return "PT0S"
+	}
+	var buf strings.Builder
+	u := uint64(d)
+	if d < 0 {
+		buf.WriteByte('-')
+		u = -u
+	}
+	buf.WriteString("PT")
+	if h := u / uint64(time.Hour); h > 0 {
+		fmt.Fprintf(&buf, "%dH", h)
+	}
+	if m := u % uint64(time.Hour) / uint64(time.Minute); m > 0 {
+		fmt.Fprintf(&buf, "%dM", m)
+	}
+	if ns := u % uint64(time.Minute); ns > 0 {
+		fmt.Fprintf(&buf, "%d", ns/uint64(time.Second))
+		if frac := ns % uint64(time.Second); frac > 0 {
+			buf.WriteString(strings.TrimRight(fmt.Sprintf(".%09d", frac), "0"))
+		}
+		buf.WriteByte('S')
+	}
+	return buf.String()
+}
+
+// parseISODuration reads an ISO-8601 duration. Weeks and days are taken to be
+// 7 and 1 multiples of 24 hours; years and months are refused because their
+// length varies.
+func parseISODuration(s string) (time.Duration, error) {
+	bad := func(why string) (time.Duration, error) {
+		return 0, fmt.Errorf("invalid ISO-8601 duration %q: %s", s, why)
+	}
+	n := s
+	neg := false
+	if len(n) > 0 && (n[0] == '-' || n[0] == '+') {
+		neg = n[0] == '-'
+		n = n[1:]
+	}
+	if len(n) < 2 || n[0] != 'P' {
+		return bad("must start with P")
+	}
+	n = n[1:]
+	var total int64
+	add := func(v, unit int64) bool {
+		if v > (math.MaxInt64-total)/unit {
+			return false
+		}
+		total += v * unit
+		return true
+	}
+	inTime := false
+	for len(n) > 0 {
+		if n[0] == 'T' {
+			if inTime || len(n) == 1 {
+				return bad("misplaced T")
+			}
+			inTime = true
+			n = n[1:]
+			continue
+		}
+		end := strings.IndexAny(n, "WDHMS")
+		if end <= 0 {
+			return bad("expected a number followed by a unit")
+		}
+		num, unit := n[:end], n[end]
+		n = n[end+1:]
+		if unit == 'S' && inTime {
+			whole, frac, _ := strings.Cut(strings.Replace(num, ",", ".", 1), ".")
+			if whole == "" {
+				whole = "0"
+			}
+			sec, err := strconv.ParseInt(whole, 10, 64)
+			if err != nil || sec < 0 || len(frac) > 9 || strings.Trim(frac, "0123456789") != "" {
+				return bad("invalid seconds")
+			}
+			ns, _ := strconv.ParseInt((frac + "000000000")[:9], 10, 64)
+			if !add(sec, int64(time.Second)) || !add(ns, 1) {
+				return bad("out of range")
+			}
+			continue
+		}
+		v, err := strconv.ParseInt(num, 10, 64)
+		if err != nil || v < 0 {
+			return bad("invalid number " + strconv.Quote(num))
+		}
+		var scale time.Duration
+		switch {
+		case unit == 'W' && !inTime:
+			scale = 7 * 24 * time.Hour
+		case unit == 'D' && !inTime:
+			scale = 24 * time.Hour
+		case unit == 'H' && inTime:
+			scale = time.Hour
+		case unit == 'M' && inTime:
+			scale = time.Minute
+		default:
+			return bad("unsupported unit " + string(unit))
+		}
+		if !add(v, int64(scale)) {
+			return bad("out of range")
+		}
+	}
+	if neg {
+		total = -total
+	}
+	return time.Duration(total), nil
+}
+
+// encTTLDuration writes a duration as the time that far from now, in epoch
+// seconds, for a TTL attribute.
+func encTTLDuration(s interface{}, f int) types.AttributeValue {
+	t := genClock().Add(readF(s, f).Interface().(time.Duration)).Unix()
+	return &types.AttributeValueMemberN{Value: strconv.FormatInt(t, 10)}
+}
+func decTTLDuration(s interface{}, f int, av types.AttributeValue) {
+	nv, err := strconv.ParseInt(av.(*types.AttributeValueMemberN).Value, 10, 64)
+	if err != nil {
+		panic(err)
+	}
+	getF(s, f).SetInt(int64(time.Unix(nv, 0).Sub(genClock()).Truncate(time.Second)))
+}
@@ -1,6 +1,7 @@
 package ddbstruct
 
 import (
+	"reflect"
 	"testing"
 	"time"
 
@@ -161,3 +162,268 @@ func TestDurationPtrSecRoundTrip(t *testing.T) {
 		t.Fatalf("expected %v, got %v", *in.X, *out.X)
 	}
 }
+
+func TestTimeMillisMicrosRoundTrip(t *testing.T) {
+	type z struct {
+		A time.Time     `ddb:"t=millis"`
+		B time.Time     `ddb:"t=micros"`
+		C time.Duration `ddb:"t=millis"`
+		D time.Duration `ddb:"t=microseconds"`
+	}
+	ts := time.Date(2021, 6, 7, 8, 9, 10, 123456789, time.UTC)
+	in := &z{A: ts, B: ts, C: 1500 * time.Millisecond, D: 42 * time.Microsecond}
+	md := cache.get(in)
+	m, err := md.encode(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for n, v := range map[string]string{"A": "1623053350123", "B": "1623053350123456", "C": "1500", "D": "42"} {
+		if g := m[n].(*types.AttributeValueMemberN).Value; g != v {
+			t.Errorf("%s: expected %s, got %s", n, v, g)
+		}
+	}
+	out := &z{}
+	if err = md.decode(out, m, false); err != nil {
+		t.Fatal(err)
+	}
+	if !out.A.Equal(ts.Truncate(time.Millisecond)) || !out.B.Equal(ts.Truncate(time.Microsecond)) || out.C != in.C || out.D != in.D {
+		t.Fatalf("expected %+v, got %+v", in, out)
+	}
+}
+
+func TestTimeStringFormatsRoundTrip(t *testing.T) {
+	type z struct {
+		A time.Time `ddb:"t=date"`
+		B time.Time `ddb:"t=rfc3339"`
+		C time.Time `ddb:"t=rfc3339,utc"`
+		D time.Time `ddb:"t=layout:2006-01-02T15"`
+		E time.Time `ddb:"utc"`
+	}
+	ts := time.Date(2021, 6, 7, 23, 9, 10, 123456789, time.FixedZone("west", -5*3600))
+	in := &z{A: ts, B: ts, C: ts, D: ts, E: ts}
+	md := cache.get(in)
+	m, err := md.encode(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for n, v := range map[string]string{
+		"A": "2021-06-07",
+		"B": "2021-06-07T23:09:10-05:00",
+		"C": "2021-06-08T04:09:10Z",
+		"D": "2021-06-07T23",
+		"E": "2021-06-08T04:09:10.123456789Z",
+	} {
+		if g := m[n].(*types.AttributeValueMemberS).Value; g != v {
+			t.Errorf("%s: expected %s, got %s", n, v, g)
+		}
+	}
+	out := &z{}
+	if err = md.decode(out, m, false); err != nil {
+		t.Fatal(err)
+	}
+	if !out.B.Equal(ts.Truncate(time.Second)) || !out.C.Equal(ts.Truncate(time.Second)) || !out.E.Equal(ts) {
+		t.Fatalf("expected %+v, got %+v", in, out)
+	}
+	if out.A.Format(time.RFC3339) != "2021-06-07T00:00:00Z" || out.D.Format(time.RFC3339) != "2021-06-07T23:00:00Z" {
+		t.Fatalf("expected %+v, got %+v", in, out)
+	}
+}
+
+func TestTimeUTCTagInvalid(t *testing.T) {
+	type z struct {
+		X string `ddb:"utc"`
+	}
+	expectPanic(t, func() { cache.get(&z{}) })
+}
+
+func TestDurationISO(t *testing.T) {
+	for d, s := range map[time.Duration]string{
+		0:                                    "PT0S",
+		90 * time.Minute:                     "PT1H30M",
+		-90 * time.Minute:                    "-PT1H30M",
+		26*time.Hour + 1500*time.Millisecond: "PT26H1.5S",
+		time.Nanosecond:                      "PT0.000000001S",
+	} {
+		if g := formatISODuration(d); g != s {
+			t.Errorf("%v: expected %s, got %s", d, s, g)
+		}
+		if g, err := parseISODuration(s); err != nil || g != d {
+			t.Errorf("%s: expected %v, got %v (%v)", s, d, g, err)
+		}
+	}
+	for s, d := range map[string]time.Duration{
+		"P1D":       24 * time.Hour,
+		"P1W":       7 * 24 * time.Hour,
+		"P1DT2H":    26 * time.Hour,
+		"PT0,5S":    500 * time.Millisecond,
+		"+PT10M":    10 * time.Minute,
+		"PT1M90S":   150 * time.Second,
+		"PT.25S":    250 * time.Millisecond,
+		"P0D":       0,
+		"PT1H0M00S": time.Hour,
+	} {
+		if g, err := parseISODuration(s); err != nil || g != d {
+			t.Errorf("%s: expected %v, got %v (%v)", s, d, g, err)
+		}
+	}
+	for _, s := range []string{"", "P", "PT", "1H", "P1Y", "P1M", "PT1D", "P1H", "PT-1H", "PT1.5H", "P1DT", "PT1.0000000001S", "PT9999999999H"} {
+		if _, err := parseISODuration(s); err == nil {
+			t.Errorf("expected %q to be invalid", s)
+		}
+	}
+}
+
+func TestDurationISORoundTrip(t *testing.T) {
+	type z struct {
+		X time.Duration `ddb:"t=iso8601"`
+	}
+	in := &z{X: 2*time.Hour + 3*time.Second}
+	f := typecalcField(t, in, 0)
+	av := f.enc(in, 0)
+	expectT(t, new(types.AttributeValueMemberS), av)
+	out := &z{}
+	f.dec(out, 0, av)
+	if in.X != out.X {
+		t.Fatalf("expected %v, got %v", in.X, out.X)
+	}
+}
+
+func TestTTLRoundTrip(t *testing.T) {
+	type z struct {
+		ID      string        `ddb:"pk"`
+		Expires time.Time     `ddb:"ttl"`
+		Hold    time.Duration `ddb:"n=hold,opt"`
+	}
+	type d struct {
+		ID      string        `ddb:"pk"`
+		Expires time.Duration `ddb:"n=expires,ttl"`
+	}
+	now := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	fixGenerators(t, now, []byte{0})
+
+	in := &z{ID: "a", Expires: now.Add(time.Hour)}
+	m, err := cache.get(in).encode(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g := attrValString(m["Expires"]); g != "1609560245" {
+		t.Fatalf("expected epoch seconds, got %s", g)
+	}
+
+	din := &d{ID: "a", Expires: 90 * time.Second}
+	md := cache.get(din)
+	m, err = md.encode(din)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g := attrValString(m["expires"]); g != "1609556735" {
+		t.Fatalf("expected epoch seconds, got %s", g)
+	}
+	exp, ok := md.expiry(m)
+	if !ok || !exp.Equal(now.Add(90*time.Second)) {
+		t.Fatalf("expected expiry at %v, got %v", now.Add(90*time.Second), exp)
+	}
+	fixGenerators(t, now.Add(30*time.Second), []byte{0})
+	dout := &d{ID: "a"}
+	if err = md.decode(dout, m, true); err != nil {
+		t.Fatal(err)
+	}
+	if dout.Expires != time.Minute {
+		t.Fatalf("expected a minute remaining, got %v", dout.Expires)
+	}
+}
+
+func TestTTLZeroIsOmitted(t *testing.T) {
+	type z struct {
+		ID      string    `ddb:"pk"`
+		Expires time.Time `ddb:"ttl"`
+	}
+	type d struct {
+		ID      string        `ddb:"pk"`
+		Expires time.Duration `ddb:"ttl"`
+	}
+	fixGenerators(t, time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC), []byte{0})
+	for _, in := range []interface{}{&z{ID: "a"}, &d{ID: "a"}} {
+		md := cache.get(in)
+		m, err := md.encode(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := m["Expires"]; ok {
+			t.Fatalf("%T: expected a zero ttl to be omitted, got %s", in, attrValString(m["Expires"]))
+		}
+		if _, ok := md.expiry(m); ok {
+			t.Fatalf("%T: expected no expiry", in)
+		}
+		out := reflect.New(reflect.TypeOf(in).Elem()).Interface()
+		if err = md.decode(out, m, false); err != nil {
+			t.Fatalf("%T: %v", in, err)
+		}
+	}
+}
+
+func TestTTLExpiry(t *testing.T) {
+	type z struct {
+		ID      string    `ddb:"pk"`
+		Expires time.Time `ddb:"ttl,opt"`
+	}
+	md := cache.get(&z{})
+	for n, e := range map[string]bool{"1609556645": true, "0": false, "-5": false, "x": false} {
+		_, ok := md.expiry(avmap{"Expires": &types.AttributeValueMemberN{Value: n}})
+		if ok != e {
+			t.Errorf("%s: expected %t, got %t", n, e, ok)
+		}
+	}
+	if _, ok := md.expiry(avmap{}); ok {
+		t.Error("expected no expiry without the attribute")
+	}
+}
+
+func TestTTLInvalid(t *testing.T) {
+	type notTime struct {
+		X int64 `ddb:"ttl"`
+	}
+	type wrongEncoding struct {
+		X time.Time `ddb:"ttl,t=nano"`
+	}
+	type twice struct {
+		X time.Time `ddb:"ttl"`
+		Y time.Time `ddb:"ttl"`
+	}
+	type onKey struct {
+		X time.Time `ddb:"ttl,pk"`
+	}
+	expectPanic(t, func() { cache.get(&notTime{}) })
+	expectPanic(t, func() { cache.get(&wrongEncoding{}) })
+	expectPanic(t, func() { cache.get(&twice{}) })
+	expectPanic(t, func() { cache.get(&onKey{}) })
+}
+
+func TestTimePtrOptions(t *testing.T) {
+	type z struct {
+		N  *time.Time     `ddb:"t=nano"`
+		E  *time.Time     `ddb:"t=epoch"`
+		M  *time.Time     `ddb:"t=millis"`
+		DN *time.Duration `ddb:"t=nano"`
+		DE *time.Duration `ddb:"t=seconds"`
+	}
+	tv := time.Unix(1609556645, 0)
+	dv := 90 * time.Second
+	in := &z{N: &tv, E: &tv, M: &tv, DN: &dv, DE: &dv}
+	m, err := cache.get(in).encode(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for n, e := range map[string]string{"N": "1609556645000000000", "E": "1609556645", "M": "1609556645000", "DN": "90000000000", "DE": "90"} {
+		if g := attrValString(m[n]); g != e {
+			t.Errorf("%s: expected %s, got %s", n, e, g)
+		}
+	}
+	out := &z{}
+	if err = cache.get(out).decode(out, m, false); err != nil {
+		t.Fatal(err)
+	}
+	if !out.N.Equal(tv) || !out.E.Equal(tv) || !out.M.Equal(tv) || *out.DN != dv || *out.DE != dv {
+		t.Fatalf("expected %v and %v, got %+v", tv, dv, out)
+	}
+}
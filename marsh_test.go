@@ -81,3 +81,15 @@ func expectPanic(t *testing.T, f func()) {
 	f()
 	t.Error("expected panic")
 }
+
+func typecalcField(t *testing.T, d interface{}, idx int) *field {
+	t.Helper()
+	f, err := parseFieldTag(reflect.TypeOf(d).Elem(), idx)
+	if err != nil {
+		t.Fatalf("cannot parse tag: %v", err)
+	}
+	if err = f.typecalc(); err != nil {
+		t.Fatalf("cannot typecalc: %v", err)
+	}
+	return f
+}
@@ -2,8 +2,8 @@ package ddbstruct
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"reflect"
 
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
@@ -49,43 +49,20 @@ func Get(ctx context.Context, svc *dynamodb.Client, table string, data interface
 		err = &NoItemError{Key: getcmd.Key}
 		return
 	}
-	dst := reflect.ValueOf(data).Elem()
-	for _, f := range dmd.f {
-		// we don't need to re-decode pk or sk into the struct; it's already there
-		if f.pk || f.sk {
-			continue
-		}
-		if av, ok := getres.Item[f.name]; !ok {
-			if f.optional {
-				if !dst.Field(f.idx).IsZero() {
-					// this case deals with the fact that, when reading the response from dynamodb, some attributes
-					// may be missing. if they're missing, and they're optional, that's fine. but if they are *also*
-					// not already at the zero value in the source struct, there isn't a way to distinguish this
-					// situation from the stale value being the value retrieved. this could be relaxed later if it
-					// seems like a feature that would be useful, but for now this feels like a footgun.
-					err = fmt.Errorf("decoding into %T: field %q is optional, value is not zero, and no attribute returned", data, f.name)
-					return
-				}
-				// optional values may be missing returned attributes, so keep going without decoding anything
-				continue
-			}
-			err = fmt.Errorf("missing attribute in response for field %q not tagged optional", f.name)
-			return
-		} else {
-			if f.dec == nil {
-				err = fmt.Errorf("field %q is missing decoder func", f.name)
-				return
-			}
-			if !dst.Field(f.idx).CanSet() {
-				err = fmt.Errorf("cannot set field %q", f.name)
-				return
-			}
-			f.dec(data, f.idx, av)
-		}
+	if exp, ok := dmd.expiry(getres.Item); ok && !exp.After(genClock()) {
+		err = &ExpiredItemError{Key: getcmd.Key, Expired: exp}
+		return
 	}
+	// we don't need to re-decode pk or sk into the struct; it's already there
+	err = dmd.decode(data, getres.Item, true)
 	return
 }
 
+// Put writes data as an item, replacing any item with the same key, as
+// PutItem does. Generated values, such as gen= fields and created and updated
+// times, are written into data first, and stay there even if the write fails.
+// If data has a created field, an item that already exists keeps its created
+// time, which is then written into data; this takes more than one request.
 func Put(ctx context.Context, svc *dynamodb.Client, table string, data interface{}) (err error) {
 	defer func() {
 		if panicVal := recover(); panicVal != nil {
@@ -98,19 +75,99 @@ func Put(ctx context.Context, svc *dynamodb.Client, table string, data interface
 	}()
 	dmd := cache.get(data)
 	putcmd := &dynamodb.PutItemInput{
-		Item:      avmap{},
 		TableName: &table,
 	}
-	for _, f := range dmd.f {
-		err = f.appendAV(putcmd.Item, data)
-		if err != nil {
-			return
-		}
+	// generated values are written back to data, so the caller can see them
+	err = dmd.generate(data)
+	if err != nil {
+		return
+	}
+	putcmd.Item, err = dmd.encode(data)
+	if err != nil {
+		return
+	}
+	if dmd.created != nil {
+		// a plain PutItem would overwrite the created time of an existing item
+		return putWithCreated(ctx, svc, dmd, putcmd, data)
 	}
 	_, err = svc.PutItem(ctx, putcmd)
 	return err
 }
 
+// putCreatedAttempts limits how many times putWithCreated tries again when
+// another writer changes the item between its reads and writes.
+const putCreatedAttempts = 5
+
+// putWithCreated writes the item with PutItem, like any other Put, but keeps
+// the created time of an item that's already stored. PutItem can't do that on
+// its own, so the item is first put only if it's new. If it isn't, the stored
+// created time is read and the item is put again with it, on condition that
+// the stored created time hasn't changed in the meantime.
+func putWithCreated(ctx context.Context, svc *dynamodb.Client, dmd structMetadata, putcmd *dynamodb.PutItemInput, data interface{}) error {
+	key, err := dmd.key(putcmd.Item)
+	if err != nil {
+		return err
+	}
+	consistent := true
+	projection := "#k, #c" // with the key, so that an item without #c still comes back
+	getcmd := &dynamodb.GetItemInput{
+		TableName:                putcmd.TableName,
+		Key:                      key,
+		ConsistentRead:           &consistent,
+		ProjectionExpression:     &projection,
+		ExpressionAttributeNames: map[string]string{"#k": dmd.pk.name, "#c": dmd.created.name},
+	}
+	for attempt := 0; attempt < putCreatedAttempts; attempt++ {
+		_, err = svc.PutItem(ctx, dmd.putIfNew(putcmd))
+		if !isConditionFailed(err) {
+			return err // a new item, or a failure that trying again won't fix
+		}
+		var getres *dynamodb.GetItemOutput
+		getres, err = svc.GetItem(ctx, getcmd)
+		if err != nil {
+			return err
+		}
+		if getres.Item == nil {
+			continue // deleted since, so try again as a new item
+		}
+		created := getres.Item[dmd.created.name]
+		_, err = svc.PutItem(ctx, dmd.putKeepCreated(putcmd, created))
+		if err == nil {
+			if hasCreated(created) { // let the caller see the created time that was kept
+				return dmd.decodeCreated(data, created)
+			}
+			return nil
+		}
+		if !isConditionFailed(err) {
+			return err
+		}
+	}
+	return fmt.Errorf("item changed during each of %d attempts to keep its created time: %w", putCreatedAttempts, err)
+}
+
+// decodeCreated sets the created field of data to av, a created time that was
+// kept from the stored item. The item has already been written by then, so a
+// stored value that can't be decoded is reported as such rather than as a
+// failure to put.
+func (md structMetadata) decodeCreated(data interface{}, av types.AttributeValue) (err error) {
+	defer func() {
+		if panicVal := recover(); panicVal != nil {
+			err = fmt.Errorf("item was put, but its stored created attribute %s could not be decoded: %v", attrValString(av), panicVal)
+		}
+	}()
+	c, err := md.created.container(data, true)
+	if err != nil {
+		return err
+	}
+	md.created.dec(c, md.created.idx, av)
+	return nil
+}
+
+func isConditionFailed(err error) bool {
+	var ccf *types.ConditionalCheckFailedException
+	return errors.As(err, &ccf)
+}
+
 func Delete(ctx context.Context, svc *dynamodb.Client, table string, data interface{}) (err error) {
 	dmd := cache.get(data)
 	delcmd := &dynamodb.DeleteItemInput{
@@ -0,0 +1,218 @@
+package ddbstruct
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+)
+
+// fakeTable is just enough of DynamoDB, behind an HTTP server, to exercise
+// the requests Put makes for an item with a created field. Items are keyed by
+// their ID attribute, and attributes are kept as their wire JSON.
+type fakeTable struct {
+	sync.Mutex
+	items map[string]map[string]json.RawMessage
+	calls []string
+
+	// beforePut, if set, runs before each PutItem is checked, to simulate
+	// another writer
+	beforePut func(ft *fakeTable, attempt int)
+	puts      int
+}
+
+type fakeRequest struct {
+	Key                       map[string]json.RawMessage
+	Item                      map[string]json.RawMessage
+	ConditionExpression       string
+	ExpressionAttributeNames  map[string]string
+	ExpressionAttributeValues map[string]json.RawMessage
+}
+
+func newFakeTable(t *testing.T) (*fakeTable, *dynamodb.Client) {
+	ft := &fakeTable{items: map[string]map[string]json.RawMessage{}}
+	srv := httptest.NewServer(ft)
+	t.Cleanup(srv.Close)
+	svc := dynamodb.New(dynamodb.Options{
+		Region:           "us-east-1",
+		EndpointResolver: dynamodb.EndpointResolverFromURL(srv.URL),
+	})
+	return ft, svc
+}
+
+func (ft *fakeTable) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ft.Lock()
+	defer ft.Unlock()
+	op := strings.TrimPrefix(r.Header.Get("X-Amz-Target"), "DynamoDB_20120810.")
+	ft.calls = append(ft.calls, op)
+	var req fakeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+	switch op {
+	case "GetItem":
+		item := ft.items[string(req.Key["ID"])]
+		if item == nil {
+			w.Write([]byte(`{}`))
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"Item": item})
+	case "PutItem":
+		ft.puts++
+		if ft.beforePut != nil {
+			ft.beforePut(ft, ft.puts)
+		}
+		key := string(req.Item["ID"])
+		if !ft.check(ft.items[key], req) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"__type":"com.amazonaws.dynamodb.v20120810#ConditionalCheckFailedException","message":"The conditional request failed"}`))
+			return
+		}
+		ft.items[key] = req.Item
+		w.Write([]byte(`{}`))
+	default:
+		http.Error(w, "unsupported operation "+op, http.StatusBadRequest)
+	}
+}
+
+// check evaluates the condition expressions that Put writes.
+func (ft *fakeTable) check(stored map[string]json.RawMessage, req fakeRequest) bool {
+	created, hasCreated := stored[req.ExpressionAttributeNames["#c"]]
+	switch req.ConditionExpression {
+	case "":
+		return true
+	case "attribute_not_exists(#k)":
+		return stored == nil
+	case "attribute_exists(#k) AND #c = :c":
+		return stored != nil && hasCreated && bytes.Equal(created, req.ExpressionAttributeValues[":c"])
+	case "attribute_exists(#k) AND (attribute_not_exists(#c) OR attribute_type(#c, :null))":
+		return stored != nil && (!hasCreated || bytes.Contains(created, []byte(`"NULL"`)))
+	}
+	panic("unexpected condition " + req.ConditionExpression)
+}
+
+type fakeCreatedItem struct {
+	ID      string    `ddb:"pk"`
+	Name    string    `ddb:"n=name"`
+	Created time.Time `ddb:"n=created,created,t=epoch"`
+}
+
+func TestPutCreatedNewItem(t *testing.T) {
+	ft, svc := newFakeTable(t)
+	now := time.Unix(2000, 0)
+	fixGenerators(t, now, []byte{0})
+	in := &fakeCreatedItem{ID: "a", Name: "new"}
+	if err := Put(context.Background(), svc, "table", in); err != nil {
+		t.Fatal(err)
+	}
+	if !in.Created.Equal(now) {
+		t.Fatalf("expected created %v, got %v", now, in.Created)
+	}
+	compareSlice(t, []string{"PutItem"}, ft.calls)
+	if g := string(ft.items[`{"S":"a"}`]["created"]); g != `{"N":"2000"}` {
+		t.Fatalf("expected the new created time to be stored, got %s", g)
+	}
+}
+
+func TestPutCreatedExistingItem(t *testing.T) {
+	ft, svc := newFakeTable(t)
+	ft.items[`{"S":"a"}`] = map[string]json.RawMessage{
+		"ID":      json.RawMessage(`{"S":"a"}`),
+		"created": json.RawMessage(`{"N":"1000"}`),
+		"extra":   json.RawMessage(`{"S":"not modelled"}`),
+	}
+	fixGenerators(t, time.Unix(2000, 0), []byte{0})
+	in := &fakeCreatedItem{ID: "a", Name: "replaced"}
+	if err := Put(context.Background(), svc, "table", in); err != nil {
+		t.Fatal(err)
+	}
+	if !in.Created.Equal(time.Unix(1000, 0)) {
+		t.Fatalf("expected the stored created time to be kept, got %v", in.Created)
+	}
+	compareSlice(t, []string{"PutItem", "GetItem", "PutItem"}, ft.calls)
+	item := ft.items[`{"S":"a"}`]
+	if string(item["created"]) != `{"N":"1000"}` || string(item["name"]) != `{"S":"replaced"}` {
+		t.Fatalf("expected the new item with the old created time, got %v", item)
+	}
+	if _, ok := item["extra"]; ok {
+		t.Fatal("expected the whole item to be replaced, as PutItem does")
+	}
+}
+
+func TestPutCreatedStoredNull(t *testing.T) {
+	ft, svc := newFakeTable(t)
+	ft.items[`{"S":"a"}`] = map[string]json.RawMessage{
+		"ID":      json.RawMessage(`{"S":"a"}`),
+		"created": json.RawMessage(`{"NULL":true}`),
+	}
+	now := time.Unix(2000, 0)
+	fixGenerators(t, now, []byte{0})
+	in := &fakeCreatedItem{ID: "a", Name: "x"}
+	if err := Put(context.Background(), svc, "table", in); err != nil {
+		t.Fatal(err)
+	}
+	if !in.Created.Equal(now) || string(ft.items[`{"S":"a"}`]["created"]) != `{"N":"2000"}` {
+		t.Fatalf("expected a NULL created time to be replaced, got %v and %s", in.Created, ft.items[`{"S":"a"}`]["created"])
+	}
+}
+
+func TestPutCreatedStoredUndecodable(t *testing.T) {
+	ft, svc := newFakeTable(t)
+	ft.items[`{"S":"a"}`] = map[string]json.RawMessage{
+		"ID":      json.RawMessage(`{"S":"a"}`),
+		"created": json.RawMessage(`{"S":"yesterday"}`),
+	}
+	fixGenerators(t, time.Unix(2000, 0), []byte{0})
+	err := Put(context.Background(), svc, "table", &fakeCreatedItem{ID: "a", Name: "x"})
+	if err == nil || !strings.Contains(err.Error(), "item was put") {
+		t.Fatalf("expected an error saying the item was written, got %v", err)
+	}
+	if string(ft.items[`{"S":"a"}`]["name"]) != `{"S":"x"}` {
+		t.Fatal("expected the item to have been written")
+	}
+}
+
+func TestPutCreatedRace(t *testing.T) {
+	ft, svc := newFakeTable(t)
+	ft.items[`{"S":"a"}`] = map[string]json.RawMessage{
+		"ID":      json.RawMessage(`{"S":"a"}`),
+		"created": json.RawMessage(`{"N":"1000"}`),
+	}
+	ft.beforePut = func(ft *fakeTable, attempt int) {
+		if attempt == 2 { // another writer gets in between the GetItem and the PutItem
+			ft.items[`{"S":"a"}`]["created"] = json.RawMessage(`{"N":"1500"}`)
+		}
+	}
+	fixGenerators(t, time.Unix(2000, 0), []byte{0})
+	in := &fakeCreatedItem{ID: "a", Name: "x"}
+	if err := Put(context.Background(), svc, "table", in); err != nil {
+		t.Fatal(err)
+	}
+	compareSlice(t, []string{"PutItem", "GetItem", "PutItem", "PutItem", "GetItem", "PutItem"}, ft.calls)
+	if !in.Created.Equal(time.Unix(1500, 0)) {
+		t.Fatalf("expected the created time written by the other writer, got %v", in.Created)
+	}
+
+	// a writer that always wins runs out the attempts
+	ft.calls = nil
+	ft.beforePut = func(ft *fakeTable, attempt int) {
+		ft.items[`{"S":"a"}`]["created"] = json.RawMessage(`{"N":"` + strconv.Itoa(attempt) + `"}`)
+	}
+	err := Put(context.Background(), svc, "table", &fakeCreatedItem{ID: "a", Name: "y"})
+	if err == nil || !strings.Contains(err.Error(), "attempts") {
+		t.Fatalf("expected to give up after repeated races, got %v", err)
+	}
+	if len(ft.calls) != 3*putCreatedAttempts {
+		t.Fatalf("expected %d requests, got %v", 3*putCreatedAttempts, ft.calls)
+	}
+}
@@ -1,7 +1,10 @@
 package ddbstruct
 
 import (
+	"fmt"
 	"reflect"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 )
 
 func maybealloc(v reflect.Value) reflect.Value {
@@ -16,6 +19,64 @@ func maybealloc(v reflect.Value) reflect.Value {
 	return v
 }
 
+// getF returns field f of the struct d points to, for decoding. Nil pointers
+// are allocated along the way.
 func getF(d interface{}, f int) reflect.Value {
 	return maybealloc(reflect.ValueOf(d).Elem().Field(f)).Elem()
 }
+
+// readF returns field f of the struct d points to, for encoding. It never
+// modifies d: a nil pointer is read as a new zero value instead of being
+// allocated. The result is always addressable. Nested structs don't use it for
+// nil pointers, since reading a nil *T inside a T as a new T would never end.
+func readF(d interface{}, f int) reflect.Value {
+	v := reflect.ValueOf(d).Elem().Field(f)
+	if v.Kind() != reflect.Pointer {
+		return v
+	}
+	if v.IsNil() {
+		return reflect.New(v.Type().Elem()).Elem()
+	}
+	return v.Elem()
+}
+
+// boxType returns a struct type with a single exported field of type t. It
+// lets a value that isn't a struct field (a slice element, a map value) be
+// handed to an encodeFunc or decodeFunc as field 0 of a struct.
+func boxType(t reflect.Type) reflect.Type {
+	return reflect.StructOf([]reflect.StructField{{Name: "V", Type: t}})
+}
+
+// indirectType returns the type that t points to, or t if it isn't a pointer.
+func indirectType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Pointer {
+		return t.Elem()
+	}
+	return t
+}
+
+// canBeNil reports whether values of type t can be nil.
+func canBeNil(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface:
+		return true
+	}
+	return false
+}
+
+func isNil(v reflect.Value) bool {
+	return canBeNil(v.Type()) && v.IsNil()
+}
+
+// decodeNull handles a NULL attribute by setting v to nil, reporting whether
+// av was NULL. A NULL attribute can't be decoded into a type that can't be nil.
+func decodeNull(v reflect.Value, av types.AttributeValue) bool {
+	if _, ok := av.(*types.AttributeValueMemberNULL); !ok {
+		return false
+	}
+	if !canBeNil(v.Type()) {
+		panic(fmt.Errorf("cannot decode NULL into %s", v.Type()))
+	}
+	v.Set(reflect.Zero(v.Type()))
+	return true
+}
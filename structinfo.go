@@ -3,13 +3,22 @@ package ddbstruct
 import (
 	"fmt"
 	"reflect"
+	"strconv"
 	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 )
 
 type structMetadata struct {
-	f  []field
-	pk *field
-	sk *field
+	f   []field
+	pk  *field
+	sk  *field
+	gen []*field // fields with generated values, including created and updated times
+
+	created *field // kept from any stored item by putWithCreated's conditional puts
+	ttl     *field
 }
 
 type structMetadataCache struct {
@@ -37,19 +46,16 @@ func (c *structMetadataCache) get(d interface{}) structMetadata {
 	}
 
 	ret := structMetadata{}
-	for n := 0; n < dte.NumField(); n++ {
-		if !dte.Field(n).IsExported() {
-			continue
-		}
-		stv, err := parseFieldTag(dte, n)
-		if err != nil {
-			panic(fmt.Errorf("cannot parse tags on field %d of struct %s: %w", n, dte, err))
-		}
-		err = stv.typecalc()
-		if err != nil {
-			panic(fmt.Errorf("unable to typecalc field %d of struct %s: %w", n, dte, err))
-		}
-		ret.f = append(ret.f, *stv)
+	fields, err := collectFields(dte, nil, map[reflect.Type]bool{})
+	if err != nil {
+		panic(err)
+	}
+	ret.f, err = resolveFields(dte, fields)
+	if err != nil {
+		panic(err)
+	}
+	for n := range ret.f {
+		stv := &ret.f[n]
 		if stv.pk {
 			if ret.pk != nil {
 				panic(fmt.Errorf("field %q tagged as pk, but pk is already tagged on field %q", stv.name, ret.pk.name))
@@ -57,7 +63,7 @@ func (c *structMetadataCache) get(d interface{}) structMetadata {
 			if stv.optional {
 				panic(fmt.Errorf("field %q tagged as pk, but also tagged as optional", stv.name))
 			}
-			ret.pk = &ret.f[n]
+			ret.pk = stv
 		}
 		if stv.sk {
 			if ret.sk != nil {
@@ -69,14 +75,61 @@ func (c *structMetadataCache) get(d interface{}) structMetadata {
 			if stv.optional {
 				panic(fmt.Errorf("field %q tagged as sk, but also tagged as optional", stv.name))
 			}
-			ret.sk = &ret.f[n]
+			ret.sk = stv
+		}
+		if stv.null {
+			if stv.optional {
+				panic(fmt.Errorf("field %q tagged as null, but also tagged as optional", stv.name))
+			}
+			if stv.pk || stv.sk {
+				panic(fmt.Errorf("field %q tagged as null, but key attributes cannot be NULL", stv.name))
+			}
+			if !canBeNil(stv.gotype) {
+				panic(fmt.Errorf("field %q tagged as null, but type %s cannot be nil", stv.name, stv.gotype))
+			}
+		}
+		if stv.utc && indirectType(stv.gotype) != typeTime {
+			panic(fmt.Errorf("field %q tagged as utc, but type %s is not a time", stv.name, stv.gotype))
+		}
+		if stv.gen != "" || stv.created || stv.updated {
+			if stv.defvalue != "" {
+				panic(fmt.Errorf("field %q has a generated value, but is also tagged with default value %q", stv.name, stv.defvalue))
+			}
+			if stv.pk || stv.sk {
+				if stv.created || stv.updated {
+					panic(fmt.Errorf("field %q is a key, so it cannot be tagged as a created or updated time", stv.name))
+				}
+			}
+			if err := stv.gencalc(); err != nil {
+				panic(err)
+			}
+			ret.gen = append(ret.gen, stv)
+		}
+		if stv.ttl {
+			if ret.ttl != nil {
+				panic(fmt.Errorf("field %q tagged as ttl, but ttl is already tagged on field %q", stv.name, ret.ttl.name))
+			}
+			if stv.pk || stv.sk {
+				panic(fmt.Errorf("field %q is a key, so it cannot be tagged as ttl", stv.name))
+			}
+			if stv.defvalue == "" {
+				// a zero TTL means the item doesn't expire, so the attribute is left out
+				stv.optional = true
+			}
+			ret.ttl = stv
+		}
+		if stv.created {
+			if ret.created != nil {
+				panic(fmt.Errorf("field %q tagged as created, but created is already tagged on field %q", stv.name, ret.created.name))
+			}
+			ret.created = stv
 		}
 		if stv.defvalue != "" {
 			if stv.optional {
 				panic(fmt.Errorf("field %q tagged with default value %q, but also tagged as optional", stv.name, stv.defvalue))
 			}
-			if stv.gotype.Kind() != reflect.String {
-				panic(fmt.Errorf("field %q tagged with default value %q, but type %s (ie %s) does not support a default value", stv.name, stv.defvalue, stv.gotype, stv.gotype.Kind()))
+			if err := stv.defcalc(); err != nil {
+				panic(err)
 			}
 		}
 	}
@@ -84,3 +137,249 @@ func (c *structMetadataCache) get(d interface{}) structMetadata {
 	c.types[dt.Elem()] = ret
 	return ret
 }
+
+// collectFields returns the fields of t, including the fields of embedded
+// structs, which are promoted into t the way encoding/json promotes them. via
+// is the index path from the outermost struct to t, and embedding tracks the
+// struct types on that path so a struct can't embed itself forever.
+func collectFields(t reflect.Type, via []int, embedding map[reflect.Type]bool) ([]field, error) {
+	embedding[t] = true
+	defer delete(embedding, t)
+
+	var ret []field
+	for n := 0; n < t.NumField(); n++ {
+		sf := t.Field(n)
+		et := embeddedStruct(sf)
+		if !sf.IsExported() && et == nil {
+			continue
+		}
+		stv, err := parseFieldTag(t, n)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse tags on field %d of struct %s: %w", n, t, err)
+		}
+		stv.via = via
+		if !sf.IsExported() && stv.name != sf.Name {
+			// named, it would be a nested struct field, and an unexported field
+			// can't be read or set through reflect, so it's left out
+			continue
+		}
+		if et != nil && stv.name == sf.Name {
+			// an embedded struct with no name in its tag is flattened into its parent
+			if stv.pk || stv.sk || stv.optional || stv.enctype != "" || stv.defvalue != "" {
+				return nil, fmt.Errorf("embedded struct field %s of struct %s can only be tagged with a name (n=)", sf.Name, t)
+			}
+			if embedding[et] {
+				return nil, fmt.Errorf("struct %s embeds itself through field %s", et, sf.Name)
+			}
+			inner, err := collectFields(et, append(via[:len(via):len(via)], n), embedding)
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, inner...)
+			continue
+		}
+		err = stv.typecalc()
+		if err != nil {
+			return nil, fmt.Errorf("unable to typecalc field %d of struct %s: %w", n, t, err)
+		}
+		ret = append(ret, *stv)
+	}
+	return ret, nil
+}
+
+// embeddedStruct returns the struct type of an embedded field that should be
+// flattened into its parent, or nil if sf should be treated as a normal field.
+// Embedded types that know how to encode themselves are not flattened.
+func embeddedStruct(sf reflect.StructField) reflect.Type {
+	if !sf.Anonymous {
+		return nil
+	}
+	if c, _ := codecs.lookup(sf.Type); c != nil {
+		return nil
+	}
+	t := sf.Type
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || isAttributeEncoder(t) || isTextEncoder(t) || isJSONEncoder(t) || isBinEncoder(t) {
+		return nil
+	}
+	return t
+}
+
+// resolveFields picks which field provides each attribute name. As with
+// encoding/json, a field from a shallower struct hides fields of the same name
+// from structs embedded deeper down, but two fields at the same depth conflict.
+func resolveFields(t reflect.Type, fields []field) ([]field, error) {
+	shallowest := map[string]int{}
+	for n := range fields {
+		if d, ok := shallowest[fields[n].name]; !ok || len(fields[n].via) < d {
+			shallowest[fields[n].name] = len(fields[n].via)
+		}
+	}
+	var ret []field
+	provider := map[string]*field{}
+	for n := range fields {
+		f := &fields[n]
+		if len(f.via) > shallowest[f.name] {
+			continue // hidden by a shallower field
+		}
+		if p, ok := provider[f.name]; ok {
+			return nil, fmt.Errorf("attribute %q of struct %s is provided by both %s and %s", f.name, t, p.path(t), f.path(t))
+		}
+		provider[f.name] = f
+		ret = append(ret, *f)
+	}
+	return ret, nil
+}
+
+// generate fills in every zero field of d that has a generated value.
+func (md structMetadata) generate(d interface{}) error {
+	for _, f := range md.gen {
+		if err := f.generate(d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expiry returns the TTL stored in item, if md has a TTL field and item has a
+// positive TTL (DynamoDB ignores anything else).
+func (md structMetadata) expiry(item avmap) (time.Time, bool) {
+	if md.ttl == nil {
+		return time.Time{}, false
+	}
+	av, ok := item[md.ttl.name].(*types.AttributeValueMemberN)
+	if !ok {
+		return time.Time{}, false
+	}
+	sec, err := strconv.ParseInt(av.Value, 10, 64)
+	if err != nil || sec <= 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(sec, 0), true
+}
+
+// key picks the pk and sk attributes out of item.
+func (md structMetadata) key(item avmap) (avmap, error) {
+	if md.pk == nil {
+		return nil, fmt.Errorf("no field is tagged as the partitioning key (pk)")
+	}
+	key := avmap{}
+	for _, f := range []*field{md.pk, md.sk} {
+		if f == nil {
+			continue
+		}
+		av, ok := item[f.name]
+		if !ok {
+			return nil, fmt.Errorf("key attribute %q is missing", f.name)
+		}
+		key[f.name] = av
+	}
+	return key, nil
+}
+
+// putIfNew returns a copy of put that only writes the item if there isn't
+// already one with the same key.
+func (md structMetadata) putIfNew(put *dynamodb.PutItemInput) *dynamodb.PutItemInput {
+	cond := "attribute_not_exists(#k)"
+	p := *put
+	p.ConditionExpression = &cond
+	p.ExpressionAttributeNames = map[string]string{"#k": md.pk.name}
+	return &p
+}
+
+// putKeepCreated returns a copy of put that writes the item with created, the
+// created attribute of the item already stored, and only if that item is still
+// there with the same created attribute. If the stored item has no created
+// time (created is nil or NULL), the item's own is written instead.
+func (md structMetadata) putKeepCreated(put *dynamodb.PutItemInput, created types.AttributeValue) *dynamodb.PutItemInput {
+	p := *put
+	p.Item = make(avmap, len(put.Item))
+	for k, av := range put.Item {
+		p.Item[k] = av
+	}
+	p.ExpressionAttributeNames = map[string]string{"#k": md.pk.name, "#c": md.created.name}
+	cond := "attribute_exists(#k) AND (attribute_not_exists(#c) OR attribute_type(#c, :null))"
+	p.ExpressionAttributeValues = avmap{":null": &types.AttributeValueMemberS{Value: "NULL"}}
+	if hasCreated(created) {
+		cond = "attribute_exists(#k) AND #c = :c"
+		p.Item[md.created.name] = created
+		p.ExpressionAttributeValues = avmap{":c": created}
+	}
+	p.ConditionExpression = &cond
+	return &p
+}
+
+// hasCreated reports whether av, a stored created attribute, holds a time.
+func hasCreated(av types.AttributeValue) bool {
+	if av == nil {
+		return false
+	}
+	_, null := av.(*types.AttributeValueMemberNULL)
+	return !null
+}
+
+// encode builds the attributes for every field of d, which must be a pointer
+// to the struct type described by md.
+func (md structMetadata) encode(d interface{}) (avmap, error) {
+	m := avmap{}
+	for _, f := range md.f {
+		if err := f.appendAV(m, d); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// decode sets the fields of d from the attributes in item. If skipKeys is set,
+// the pk and sk fields are left alone.
+func (md structMetadata) decode(d interface{}, item avmap, skipKeys bool) error {
+	for _, f := range md.f {
+		if skipKeys && (f.pk || f.sk) {
+			continue
+		}
+		av, ok := item[f.name]
+		if !ok {
+			c, err := f.container(d, false)
+			if err != nil {
+				return err
+			}
+			if c == nil {
+				// fields behind a nil embedded pointer are left out when encoding, so
+				// leave the pointer nil unless one of them is actually present
+				continue
+			}
+			dst := reflect.ValueOf(c).Elem()
+			if f.optional {
+				if !dst.Field(f.idx).IsZero() {
+					// this case deals with the fact that, when reading the response from dynamodb, some attributes
+					// may be missing. if they're missing, and they're optional, that's fine. but if they are *also*
+					// not already at the zero value in the source struct, there isn't a way to distinguish this
+					// situation from the stale value being the value retrieved. this could be relaxed later if it
+					// seems like a feature that would be useful, but for now this feels like a footgun.
+					return fmt.Errorf("decoding into %T: field %q is optional, value is not zero, and no attribute returned", d, f.name)
+				}
+				// optional values may be missing returned attributes, so keep going without decoding anything
+				continue
+			}
+			return fmt.Errorf("missing attribute in response for field %q not tagged optional", f.name)
+		}
+		if f.dec == nil {
+			return fmt.Errorf("field %q is missing decoder func", f.name)
+		}
+		c, err := f.container(d, true)
+		if err != nil {
+			return fmt.Errorf("decoding into %T: %w", d, err)
+		}
+		dst := reflect.ValueOf(c).Elem()
+		if !dst.Field(f.idx).CanSet() {
+			return fmt.Errorf("cannot set field %q", f.name)
+		}
+		if decodeNull(dst.Field(f.idx), av) {
+			continue
+		}
+		f.dec(c, f.idx, av)
+	}
+	return nil
+}
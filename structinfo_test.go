@@ -0,0 +1,233 @@
+package ddbstruct
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+type TestAudit struct {
+	CreatedBy string    `ddb:"n=created_by"`
+	Created   time.Time `ddb:"n=created"`
+}
+
+type TestTenantScoped struct {
+	Tenant string `ddb:"n=tenant"`
+}
+
+func TestEmbeddedFlattenRoundTrip(t *testing.T) {
+	type z struct {
+		ID string `ddb:"pk"`
+		TestAudit
+		*TestTenantScoped
+	}
+	in := &z{ID: "a", TestAudit: TestAudit{CreatedBy: "me", Created: time.Unix(1000, 0).UTC()}, TestTenantScoped: &TestTenantScoped{Tenant: "acme"}}
+	md := cache.get(in)
+	m, err := md.encode(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, n := range []string{"ID", "created_by", "created", "tenant"} {
+		if _, ok := m[n]; !ok {
+			t.Fatalf("expected attribute %q, got %v", n, m)
+		}
+	}
+	out := &z{ID: "a"}
+	if err = md.decode(out, m, true); err != nil {
+		t.Fatal(err)
+	}
+	if out.TestTenantScoped == nil {
+		t.Fatal("embedded pointer was not allocated")
+	}
+	if in.TestAudit != out.TestAudit || *in.TestTenantScoped != *out.TestTenantScoped {
+		t.Fatalf("expected %+v, got %+v", in, out)
+	}
+}
+
+func TestEmbeddedNilPointer(t *testing.T) {
+	type z struct {
+		ID string `ddb:"pk"`
+		*TestTenantScoped
+	}
+	in := &z{ID: "a"}
+	m, err := cache.get(in).encode(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if in.TestTenantScoped != nil {
+		t.Fatal("encoding allocated the embedded pointer")
+	}
+	if _, ok := m["tenant"]; ok {
+		t.Fatalf("expected fields behind a nil embedded pointer to be omitted, got %v", m)
+	}
+	out := &z{}
+	if err = cache.get(out).decode(out, m, false); err != nil {
+		t.Fatal(err)
+	}
+	if out.TestTenantScoped != nil {
+		t.Fatal("decoding allocated the embedded pointer with none of its attributes present")
+	}
+}
+
+func TestEmbeddedNamedIsNested(t *testing.T) {
+	type z struct {
+		ID               string `ddb:"pk"`
+		TestTenantScoped `ddb:"n=scope"`
+	}
+	in := &z{ID: "a", TestTenantScoped: TestTenantScoped{Tenant: "acme"}}
+	m, err := cache.get(in).encode(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectT(t, new(types.AttributeValueMemberM), m["scope"])
+	if _, ok := m["tenant"]; ok {
+		t.Fatal("named embedded struct was flattened")
+	}
+}
+
+func TestEmbeddedShallowerWins(t *testing.T) {
+	type z struct {
+		ID string `ddb:"pk"`
+		TestTenantScoped
+		Tenant int64 `ddb:"n=tenant"`
+	}
+	in := &z{ID: "a", Tenant: 5}
+	m, err := cache.get(in).encode(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectT(t, new(types.AttributeValueMemberN), m["tenant"])
+}
+
+func TestEmbeddedConflict(t *testing.T) {
+	type other struct {
+		Name string `ddb:"n=tenant"`
+	}
+	type Other other
+	type z struct {
+		ID string `ddb:"pk"`
+		TestTenantScoped
+		Other
+	}
+	expectPanic(t, func() { cache.get(&z{}) })
+}
+
+func TestEmbeddedTagOptions(t *testing.T) {
+	type z struct {
+		ID               string `ddb:"pk"`
+		TestTenantScoped `ddb:"opt"`
+	}
+	expectPanic(t, func() { cache.get(&z{}) })
+}
+
+func TestKeyAfterUnexportedField(t *testing.T) {
+	type z struct {
+		hidden int
+		ID     string `ddb:"pk"`
+	}
+	md := cache.get(&z{})
+	if md.pk == nil || md.pk.name != "ID" {
+		t.Fatalf("expected pk to be ID, got %+v", md.pk)
+	}
+}
+
+func TestEncodeDoesNotModify(t *testing.T) {
+	type z struct {
+		S *string
+		I *int64
+		T *time.Time
+		A *testAddress
+		L []*testAddress
+		B *[]byte
+	}
+	in := &z{L: []*testAddress{nil}}
+	m, err := cache.get(in).encode(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if in.S != nil || in.I != nil || in.T != nil || in.A != nil || in.L[0] != nil || in.B != nil {
+		t.Fatalf("encoding allocated into the source struct: %+v", in)
+	}
+	if s := m["S"].(*types.AttributeValueMemberS).Value; s != "" {
+		t.Fatalf("expected nil pointer to encode as zero, got %q", s)
+	}
+}
+
+func TestEncodeConcurrently(t *testing.T) {
+	type z struct {
+		S *string
+		A *testAddress
+		M map[string]*int64
+	}
+	in := &z{M: map[string]*int64{"nil": nil}}
+	md := cache.get(in)
+	done := make(chan error)
+	for n := 0; n < 4; n++ {
+		go func() {
+			_, err := md.encode(in)
+			done <- err
+		}()
+	}
+	for n := 0; n < 4; n++ {
+		if err := <-done; err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+type testAuditUnexported struct {
+	Created string
+	note    string
+}
+
+func TestEmbeddedUnexportedType(t *testing.T) {
+	type z struct {
+		ID string `ddb:"pk"`
+		testAuditUnexported
+	}
+	in := &z{ID: "x", testAuditUnexported: testAuditUnexported{Created: "c", note: "n"}}
+	md := cache.get(in)
+	m, err := md.encode(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m) != 2 || attrValString(m["Created"]) != `"c"` {
+		t.Fatalf("expected ID and the promoted Created, got %v", m)
+	}
+	out := &z{}
+	if err = md.decode(out, m, false); err != nil {
+		t.Fatal(err)
+	}
+	if out.ID != "x" || out.Created != "c" || out.note != "" {
+		t.Fatalf("expected ID and Created to be decoded, got %+v", out)
+	}
+}
+
+func TestEmbeddedUnexportedPointer(t *testing.T) {
+	type z struct {
+		ID string `ddb:"pk"`
+		*testAuditUnexported
+	}
+	in := &z{ID: "x", testAuditUnexported: &testAuditUnexported{Created: "c"}}
+	md := cache.get(in)
+	m, err := md.encode(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attrValString(m["Created"]) != `"c"` {
+		t.Fatalf("expected the promoted Created, got %v", m)
+	}
+	out := &z{testAuditUnexported: &testAuditUnexported{}}
+	if err = md.decode(out, m, false); err != nil {
+		t.Fatal(err)
+	}
+	if out.Created != "c" {
+		t.Fatalf("expected Created to be decoded, got %+v", out.testAuditUnexported)
+	}
+	// a nil pointer of an unexported type can't be allocated to decode into
+	if err = md.decode(&z{}, m, false); err == nil || !strings.Contains(err.Error(), "unexported") {
+		t.Fatalf("expected an error allocating the unexported embedded pointer, got %v", err)
+	}
+}
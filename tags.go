@@ -10,10 +10,16 @@ var fieldTagMatcher = regexp.MustCompile(
 	"^(" + // anchor to beginning of tag string
 		"(?P<pk>pk)" + // partition key flag
 		"|(?P<sk>sk)" + // sort key flag
-		"|(n=(?P<n>.+))" + // field name override
-		"|(t=(?P<t>.+))" + // field type override
-		"|(def=(?P<def>.+))" + // default value
+		"|(n=(?P<n>[^,]+))" + // field name override
+		"|(t=(?P<t>[^,]+))" + // field type override
+		"|(def=(?P<def>[^,]+))" + // default value
+		"|(gen=(?P<gen>[^,]+))" + // generated value
 		"|(?P<opt>opt)" + // optional flag
+		"|(?P<null>null)" + // write NULL for nil values
+		"|(?P<utc>utc)" + // convert times to UTC before encoding
+		"|(?P<created>created)" + // creation time, set only when the item is new
+		"|(?P<updated>updated)" + // modification time, set on every put
+		"|(?P<ttl>ttl)" + // expiry time, in epoch seconds; omitted when zero
 		")(,|$)") // match the end of the string or a comma
 
 func parseFieldTag(t reflect.Type, idx int) (*field, error) {
@@ -40,12 +46,24 @@ func parseFieldTag(t reflect.Type, idx int) (*field, error) {
 				ret.sk = true
 			case "opt":
 				ret.optional = true
+			case "null":
+				ret.null = true
+			case "utc":
+				ret.utc = true
+			case "created":
+				ret.created = true
+			case "updated":
+				ret.updated = true
+			case "ttl":
+				ret.ttl = true
 			case "n":
 				ret.name = v
 			case "t":
 				ret.enctype = v
 			case "def":
 				ret.defvalue = v
+			case "gen":
+				ret.gen = v
 			}
 		}
 		tagdata = tagdata[len(subexp[0]):]
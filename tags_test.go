@@ -0,0 +1,19 @@
+package ddbstruct
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTagOptionsAfterValue(t *testing.T) {
+	type z struct {
+		X string `ddb:"n=x,t=string,def=none,pk"`
+	}
+	f, err := parseFieldTag(reflect.TypeOf(z{}), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.name != "x" || f.enctype != "string" || f.defvalue != "none" || !f.pk {
+		t.Fatalf("tag parsed incorrectly: %+v", f)
+	}
+}